@@ -0,0 +1,427 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// cephCommandKeyLabel is the label selector for the key/index of the element matched by the last wildcard.
+const cephCommandKeyLabel = "@key"
+
+// cephCommandRunner runs mon and mgr commands, implemented by `rados.Conn`.
+type cephCommandRunner interface {
+	MonCommand(args []byte) ([]byte, string, error)
+	MgrCommand(args [][]byte) ([]byte, string, error)
+}
+
+type cephCommandCacheKey struct {
+	runner cephCommandRunner
+	name   string
+}
+
+// cephCommandOutput is the (cached) JSON output of a command.
+type cephCommandOutput struct {
+	time   time.Time
+	output any
+}
+
+// cephCommandSelectorSegment is a key, index or wildcard of a selector.
+type cephCommandSelectorSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// cephCommandMatch is a value matched by a selector.
+type cephCommandMatch struct {
+	value any
+	// element is the element matched by the last wildcard (the root if there is no wildcard) and its key/index
+	element any
+	key     string
+}
+
+type CephCommands struct {
+	current *prometheus.Desc
+
+	mutex sync.Mutex
+	cache map[cephCommandCacheKey]*cephCommandOutput
+}
+
+func init() {
+	Factories["ceph_command"] = NewCephCommands
+}
+
+func NewCephCommands() (Collector, error) {
+	return &CephCommands{
+		cache: map[cephCommandCacheKey]*cephCommandOutput{},
+	}, nil
+}
+
+func (c *CephCommands) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	return c.update(ctx, client.Rados, client.Config.Commands, ch)
+}
+
+func (c *CephCommands) update(ctx context.Context, runner cephCommandRunner, cmds []*config.CephCommand, ch chan<- prometheus.Metric) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := validateCephCommands(cmds); err != nil {
+		return err
+	}
+
+	var errs error
+	for _, cmd := range cmds {
+		if err := ctx.Err(); err != nil {
+			return multierr.Append(errs, err)
+		}
+
+		output, err := c.output(runner, cmd)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to run %s command (prefix: %q). %w", cmd.Name, cmd.Prefix, err))
+			continue
+		}
+
+		for _, metric := range cmd.Metrics {
+			if err := c.updateMetric(output, metric, ch); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to create %s metric from %s command output. %w", metric.Name, cmd.Name, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// output returns the output of the command, the command is only run when the cached output is older than its interval.
+func (c *CephCommands) output(runner cephCommandRunner, cmd *config.CephCommand) (any, error) {
+	key := cephCommandCacheKey{
+		runner: runner,
+		name:   cmd.Name,
+	}
+
+	if cached, ok := c.cache[key]; ok && time.Since(cached.time) < cmd.Interval {
+		return cached.output, nil
+	}
+
+	output, err := runCephCommand(runner, cmd)
+	if err != nil {
+		delete(c.cache, key)
+		return nil, err
+	}
+
+	c.cache[key] = &cephCommandOutput{
+		time:   time.Now(),
+		output: output,
+	}
+
+	return output, nil
+}
+
+func (c *CephCommands) updateMetric(output any, metric *config.CephCommandMetric, ch chan<- prometheus.Metric) error {
+	selector, err := parseCephCommandSelector(metric.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector %q. %w", metric.Selector, err)
+	}
+
+	labelNames := []string{}
+	labelSelectors := map[string][]cephCommandSelectorSegment{}
+	for label, labelSelector := range metric.Labels {
+		labelNames = append(labelNames, label)
+		if labelSelector == cephCommandKeyLabel {
+			continue
+		}
+
+		segments, err := parseCephCommandSelector(labelSelector)
+		if err != nil {
+			return fmt.Errorf("invalid selector %q of %s label. %w", labelSelector, label, err)
+		}
+		labelSelectors[label] = segments
+	}
+	slices.Sort(labelNames)
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "command", metric.Name),
+		metric.Help,
+		labelNames, nil)
+
+	var errs error
+	// The same label values could be selected for multiple values
+	done := map[string]bool{}
+	for _, match := range selectCephCommandValues(output, selector, output, "") {
+		value, ok := cephCommandValue(match.value)
+		if !ok {
+			continue
+		}
+
+		labelValues := make([]string, 0, len(labelNames))
+		for _, label := range labelNames {
+			if metric.Labels[label] == cephCommandKeyLabel {
+				labelValues = append(labelValues, match.key)
+				continue
+			}
+
+			// Absolute selectors start at the root of the output
+			node := match.element
+			if strings.HasPrefix(metric.Labels[label], "$") {
+				node = output
+			}
+
+			labelValue := ""
+			if matches := selectCephCommandValues(node, labelSelectors[label], node, ""); len(matches) > 0 {
+				labelValue = cephCommandLabelValue(matches[0].value)
+			}
+			labelValues = append(labelValues, labelValue)
+		}
+
+		if done[strings.Join(labelValues, "\xff")] {
+			errs = multierr.Append(errs, fmt.Errorf("duplicate label values %v", labelValues))
+			continue
+		}
+		done[strings.Join(labelValues, "\xff")] = true
+
+		m, err := prometheus.NewConstMetric(c.current, prometheus.GaugeValue, value, labelValues...)
+		if err != nil {
+			return multierr.Append(errs, err)
+		}
+		ch <- m
+	}
+
+	return errs
+}
+
+func validateCephCommands(cmds []*config.CephCommand) error {
+	names := map[string]bool{}
+	// Metric names must be unique over all commands, the metrics would be collected twice otherwise
+	metricNames := map[string]bool{}
+	for _, cmd := range cmds {
+		if cmd.Name == "" || cmd.Prefix == "" {
+			return fmt.Errorf("ceph command name and prefix must be set (name: %q, prefix: %q)", cmd.Name, cmd.Prefix)
+		}
+		if names[cmd.Name] {
+			return fmt.Errorf("ceph command name %q is already used", cmd.Name)
+		}
+		names[cmd.Name] = true
+
+		if cmd.Target != "" && cmd.Target != config.CephCommandTargetMon && cmd.Target != config.CephCommandTargetMgr {
+			return fmt.Errorf("unknown target %q of %s ceph command", cmd.Target, cmd.Name)
+		}
+
+		for _, metric := range cmd.Metrics {
+			if metric.Name == "" || metric.Selector == "" {
+				return fmt.Errorf("ceph command %s metric name and selector must be set (name: %q, selector: %q)", cmd.Name, metric.Name, metric.Selector)
+			}
+			if metricNames[metric.Name] {
+				return fmt.Errorf("ceph command %s metric name %q is already used", cmd.Name, metric.Name)
+			}
+			metricNames[metric.Name] = true
+		}
+	}
+
+	return nil
+}
+
+func runCephCommand(runner cephCommandRunner, cmd *config.CephCommand) (any, error) {
+	args := map[string]any{}
+	for k, v := range cmd.Args {
+		args[k] = v
+	}
+	args["prefix"] = cmd.Prefix
+	args["format"] = "json"
+
+	buf, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	var status string
+	switch cmd.Target {
+	case config.CephCommandTargetMgr:
+		out, status, err = runner.MgrCommand([][]byte{buf})
+	default:
+		out, status, err = runner.MonCommand(buf)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w (status: %s)", err, status)
+	}
+
+	var output any
+	if err := json.Unmarshal(out, &output); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// parseCephCommandSelector parses a JSONPath-like selector, e.g., `$.pools[*].stats['stored']`.
+// Supported are keys (`.key`, `['key']`), indexes (`[0]`) and wildcards (`[*]`, `.*`).
+func parseCephCommandSelector(selector string) ([]cephCommandSelectorSegment, error) {
+	s := strings.TrimPrefix(selector, "$")
+	// Relative selectors can start with a key directly
+	if s != "" && s[0] != '.' && s[0] != '[' {
+		s = "." + s
+	}
+
+	segments := []cephCommandSelectorSegment{}
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			end := strings.IndexAny(s, ".[")
+			if end == -1 {
+				end = len(s)
+			}
+			key := s[:end]
+			s = s[end:]
+
+			if key == "" {
+				return nil, fmt.Errorf("empty key")
+			}
+			if key == "*" {
+				segments = append(segments, cephCommandSelectorSegment{wildcard: true})
+				continue
+			}
+			segments = append(segments, cephCommandSelectorSegment{key: key})
+
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("missing closing bracket")
+			}
+			value := s[1:end]
+			s = s[end+1:]
+
+			switch {
+			case value == "*":
+				segments = append(segments, cephCommandSelectorSegment{wildcard: true})
+			case len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0]:
+				segments = append(segments, cephCommandSelectorSegment{key: value[1 : len(value)-1]})
+			default:
+				index, err := strconv.Atoi(value)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid index %q", value)
+				}
+				segments = append(segments, cephCommandSelectorSegment{index: index, isIndex: true})
+			}
+
+		default:
+			return nil, fmt.Errorf("unexpected %q", s[0])
+		}
+	}
+
+	return segments, nil
+}
+
+// selectCephCommandValues returns the values matched by the selector segments.
+func selectCephCommandValues(node any, segments []cephCommandSelectorSegment, element any, key string) []cephCommandMatch {
+	if len(segments) == 0 {
+		return []cephCommandMatch{{
+			value:   node,
+			element: element,
+			key:     key,
+		}}
+	}
+
+	segment := segments[0]
+	switch {
+	case segment.wildcard:
+		matches := []cephCommandMatch{}
+		switch n := node.(type) {
+		case []any:
+			for i, v := range n {
+				matches = append(matches, selectCephCommandValues(v, segments[1:], v, strconv.Itoa(i))...)
+			}
+		case map[string]any:
+			// Sorted for a stable order of the matches
+			keys := make([]string, 0, len(n))
+			for k := range n {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+
+			for _, k := range keys {
+				matches = append(matches, selectCephCommandValues(n[k], segments[1:], n[k], k)...)
+			}
+		}
+		return matches
+
+	case segment.isIndex:
+		n, ok := node.([]any)
+		if !ok || segment.index >= len(n) {
+			return nil
+		}
+		return selectCephCommandValues(n[segment.index], segments[1:], element, key)
+
+	default:
+		n, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v, ok := n[segment.key]
+		if !ok {
+			return nil
+		}
+		return selectCephCommandValues(v, segments[1:], element, key)
+	}
+}
+
+// cephCommandValue returns the metric value of a JSON value, false if it isn't a number, bool or numeric string.
+func cephCommandValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func cephCommandLabelValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	// Objects and arrays are used as JSON
+	out, _ := json.Marshal(value)
+	return string(out)
+}
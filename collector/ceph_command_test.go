@@ -0,0 +1,444 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fakeCephCommandRunner returns the output of the commands by prefix.
+type fakeCephCommandRunner struct {
+	outputs map[string]string
+	err     error
+
+	monCalls int
+	mgrCalls int
+}
+
+func (r *fakeCephCommandRunner) MonCommand(args []byte) ([]byte, string, error) {
+	r.monCalls++
+	return r.run(args)
+}
+
+func (r *fakeCephCommandRunner) MgrCommand(args [][]byte) ([]byte, string, error) {
+	r.mgrCalls++
+	return r.run(args[0])
+}
+
+func (r *fakeCephCommandRunner) run(args []byte) ([]byte, string, error) {
+	if r.err != nil {
+		return nil, "command failed", r.err
+	}
+
+	cmd := map[string]any{}
+	if err := json.Unmarshal(args, &cmd); err != nil {
+		return nil, "invalid command", err
+	}
+	if cmd["format"] != "json" {
+		return nil, "invalid command", errors.New("json format not requested")
+	}
+
+	prefix, _ := cmd["prefix"].(string)
+	out, ok := r.outputs[prefix]
+	if !ok {
+		return nil, "unknown command", errors.New("unknown command " + prefix)
+	}
+
+	return []byte(out), "", nil
+}
+
+const cephCommandTestPools = `{
+  "fsid": "abc",
+  "pools": [
+    {"name": "a", "id": 1, "stats": {"stored": 10, "full": false}},
+    {"name": "b", "id": 2, "stats": {"stored": 20, "full": true}}
+  ]
+}`
+
+func TestCephCommandsUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmds    []*config.CephCommand
+		outputs map[string]string
+		want    map[string]float64
+		// wantErr is a substring of the expected error
+		wantErr string
+	}{
+		{
+			name: "relative selector with wildcard",
+			cmds: []*config.CephCommand{{
+				Name:   "df",
+				Prefix: "df",
+				Metrics: []*config.CephCommandMetric{{
+					Name:     "pool_stored",
+					Help:     "Pool stored bytes",
+					Selector: "pools[*].stats.stored",
+					Labels: map[string]string{
+						"pool":  "name",
+						"index": "@key",
+					},
+				}},
+			}},
+			outputs: map[string]string{"df": cephCommandTestPools},
+			want: map[string]float64{
+				`ceph_command_pool_stored{index="0",pool="a"}`: 10,
+				`ceph_command_pool_stored{index="1",pool="b"}`: 20,
+			},
+		},
+		{
+			name: "absolute selectors with quoted keys",
+			cmds: []*config.CephCommand{{
+				Name:   "df",
+				Prefix: "df",
+				Metrics: []*config.CephCommandMetric{{
+					Name:     "pool_full",
+					Help:     "Pool is full",
+					Selector: `$.pools[*]['stats']["full"]`,
+					Labels: map[string]string{
+						"fsid": "$.fsid",
+						"id":   "['id']",
+					},
+				}},
+			}},
+			outputs: map[string]string{"df": cephCommandTestPools},
+			want: map[string]float64{
+				`ceph_command_pool_full{fsid="abc",id="1"}`: 0,
+				`ceph_command_pool_full{fsid="abc",id="2"}`: 1,
+			},
+		},
+		{
+			name: "index selector",
+			cmds: []*config.CephCommand{{
+				Name:   "df",
+				Prefix: "df",
+				Metrics: []*config.CephCommandMetric{{
+					Name:     "second_pool_stored",
+					Help:     "Second pool stored bytes",
+					Selector: "$.pools[1].stats.stored",
+				}},
+			}},
+			outputs: map[string]string{"df": cephCommandTestPools},
+			want: map[string]float64{
+				`ceph_command_second_pool_stored{}`: 20,
+			},
+		},
+		{
+			name: "map wildcard on mgr",
+			cmds: []*config.CephCommand{{
+				Name:   "osd_perf",
+				Target: config.CephCommandTargetMgr,
+				Prefix: "osd perf",
+				Metrics: []*config.CephCommandMetric{{
+					Name:     "osd_commit_latency_ms",
+					Help:     "OSD commit latency",
+					Selector: "$.osds.*.commit_latency_ms",
+					Labels: map[string]string{
+						"osd": "@key",
+					},
+				}},
+			}},
+			outputs: map[string]string{
+				// Numeric strings are used as values, other strings are skipped
+				"osd perf": `{"osds": {"osd.0": {"commit_latency_ms": 5}, "osd.1": {"commit_latency_ms": "7.5"}, "osd.2": {"commit_latency_ms": "n/a"}}}`,
+			},
+			want: map[string]float64{
+				`ceph_command_osd_commit_latency_ms{osd="osd.0"}`: 5,
+				`ceph_command_osd_commit_latency_ms{osd="osd.1"}`: 7.5,
+			},
+		},
+		{
+			name: "duplicate label values",
+			cmds: []*config.CephCommand{{
+				Name:   "df",
+				Prefix: "df",
+				Metrics: []*config.CephCommandMetric{{
+					Name:     "pool_stored",
+					Help:     "Pool stored bytes",
+					Selector: "pools[*].stats.stored",
+					Labels: map[string]string{
+						"fsid": "$.fsid",
+					},
+				}},
+			}},
+			outputs: map[string]string{"df": cephCommandTestPools},
+			want: map[string]float64{
+				`ceph_command_pool_stored{fsid="abc"}`: 10,
+			},
+			wantErr: "duplicate label values [abc]",
+		},
+		{
+			name: "missing closing bracket",
+			cmds: []*config.CephCommand{{
+				Name:   "df",
+				Prefix: "df",
+				Metrics: []*config.CephCommandMetric{
+					{Name: "pool_stored", Selector: "pools[*.stats.stored"},
+				},
+			}},
+			outputs: map[string]string{"df": cephCommandTestPools},
+			want:    map[string]float64{},
+			wantErr: "missing closing bracket",
+		},
+		{
+			name: "invalid label selector",
+			cmds: []*config.CephCommand{{
+				Name:   "df",
+				Prefix: "df",
+				Metrics: []*config.CephCommandMetric{{
+					Name:     "pool_stored",
+					Selector: "pools[*].stats.stored",
+					Labels: map[string]string{
+						"pool": "names[x]",
+					},
+				}},
+			}},
+			outputs: map[string]string{"df": cephCommandTestPools},
+			want:    map[string]float64{},
+			wantErr: `invalid index "x"`,
+		},
+		{
+			name: "command failure",
+			cmds: []*config.CephCommand{{
+				Name:   "status",
+				Prefix: "status",
+				Metrics: []*config.CephCommandMetric{
+					{Name: "num_pools", Selector: "$.pools"},
+				},
+			}},
+			outputs: map[string]string{},
+			want:    map[string]float64{},
+			wantErr: "unknown command status",
+		},
+		{
+			name: "duplicate metric name in command",
+			cmds: []*config.CephCommand{{
+				Name:   "df",
+				Prefix: "df",
+				Metrics: []*config.CephCommandMetric{
+					{Name: "pool_stored", Selector: "pools[*].stats.stored"},
+					{Name: "pool_stored", Selector: "pools[*].id"},
+				},
+			}},
+			outputs: map[string]string{"df": cephCommandTestPools},
+			want:    map[string]float64{},
+			wantErr: `metric name "pool_stored" is already used`,
+		},
+		{
+			name: "duplicate metric name over commands",
+			cmds: []*config.CephCommand{
+				{
+					Name:   "df",
+					Prefix: "df",
+					Metrics: []*config.CephCommandMetric{
+						{Name: "pool_stored", Selector: "pools[*].stats.stored"},
+					},
+				},
+				{
+					Name:   "df_detail",
+					Prefix: "df",
+					Metrics: []*config.CephCommandMetric{
+						{Name: "pool_stored", Selector: "pools[*].stats.stored"},
+					},
+				},
+			},
+			outputs: map[string]string{"df": cephCommandTestPools},
+			want:    map[string]float64{},
+			wantErr: `metric name "pool_stored" is already used`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := &fakeCephCommandRunner{
+				outputs: test.outputs,
+			}
+
+			c, err := NewCephCommands()
+			if err != nil {
+				t.Fatalf("failed to create collector. %v", err)
+			}
+
+			got, err := gatherMetrics(t, func(ch chan<- prometheus.Metric) error {
+				return c.(*CephCommands).update(context.Background(), runner, test.cmds, ch)
+			})
+			if test.wantErr == "" && err != nil {
+				t.Errorf("unexpected error. %v", err)
+			} else if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", test.wantErr, err)
+			}
+
+			compareMetrics(t, test.want, got)
+
+			for _, cmd := range test.cmds {
+				if cmd.Target == config.CephCommandTargetMgr && runner.monCalls > 0 {
+					t.Errorf("mgr command %s has been sent to the mon", cmd.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestCephCommandsInterval(t *testing.T) {
+	cmds := []*config.CephCommand{{
+		Name:     "df",
+		Prefix:   "df",
+		Interval: time.Hour,
+		Metrics: []*config.CephCommandMetric{{
+			Name:     "pool_stored",
+			Help:     "Pool stored bytes",
+			Selector: "pools[*].stats.stored",
+			Labels: map[string]string{
+				"pool": "name",
+			},
+		}},
+	}}
+
+	runner := &fakeCephCommandRunner{
+		outputs: map[string]string{"df": cephCommandTestPools},
+	}
+
+	cc, err := NewCephCommands()
+	if err != nil {
+		t.Fatalf("failed to create collector. %v", err)
+	}
+	c := cc.(*CephCommands)
+
+	update := func() (map[string]float64, error) {
+		return gatherMetrics(t, func(ch chan<- prometheus.Metric) error {
+			return c.update(context.Background(), runner, cmds, ch)
+		})
+	}
+
+	want := map[string]float64{
+		`ceph_command_pool_stored{pool="a"}`: 10,
+		`ceph_command_pool_stored{pool="b"}`: 20,
+	}
+
+	// The cached output is used within the interval
+	for i := 0; i < 2; i++ {
+		got, err := update()
+		if err != nil {
+			t.Fatalf("unexpected error. %v", err)
+		}
+		compareMetrics(t, want, got)
+	}
+	if runner.monCalls != 1 {
+		t.Errorf("expected command to be run once within the interval, got %d runs", runner.monCalls)
+	}
+
+	// Expire the cached output, the failed command removes it from the cache
+	key := cephCommandCacheKey{runner: runner, name: "df"}
+	c.cache[key].time = time.Now().Add(-2 * time.Hour)
+	runner.err = errors.New("connection lost")
+
+	got, err := update()
+	if err == nil || !strings.Contains(err.Error(), "connection lost") {
+		t.Errorf("expected command error, got %v", err)
+	}
+	compareMetrics(t, map[string]float64{}, got)
+	if _, ok := c.cache[key]; ok {
+		t.Errorf("expected cached output to be removed after the command failed")
+	}
+
+	// The command is run again with the next update
+	runner.err = nil
+	got, err = update()
+	if err != nil {
+		t.Fatalf("unexpected error. %v", err)
+	}
+	compareMetrics(t, want, got)
+	if runner.monCalls != 3 {
+		t.Errorf("expected command to be run again after the failure, got %d runs", runner.monCalls)
+	}
+}
+
+func TestParseCephCommandSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []cephCommandSelectorSegment
+		wantErr  string
+	}{
+		{
+			selector: "pools",
+			want:     []cephCommandSelectorSegment{{key: "pools"}},
+		},
+		{
+			selector: "$.pools[*].stats",
+			want: []cephCommandSelectorSegment{
+				{key: "pools"},
+				{wildcard: true},
+				{key: "stats"},
+			},
+		},
+		{
+			selector: "$.osds.*",
+			want: []cephCommandSelectorSegment{
+				{key: "osds"},
+				{wildcard: true},
+			},
+		},
+		{
+			selector: `$['pool.name']["x"][2]`,
+			want: []cephCommandSelectorSegment{
+				{key: "pool.name"},
+				{key: "x"},
+				{index: 2, isIndex: true},
+			},
+		},
+		{
+			selector: "$[*]",
+			want:     []cephCommandSelectorSegment{{wildcard: true}},
+		},
+		{
+			selector: "$",
+			want:     []cephCommandSelectorSegment{},
+		},
+		{selector: "pools..stats", wantErr: "empty key"},
+		{selector: "pools[0", wantErr: "missing closing bracket"},
+		{selector: "pools[-1]", wantErr: `invalid index "-1"`},
+		{selector: "pools['x]", wantErr: `invalid index "'x"`},
+		{selector: "pools[0]x", wantErr: `unexpected 'x'`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.selector, func(t *testing.T) {
+			got, err := parseCephCommandSelector(test.selector)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Errorf("expected error %q, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error. %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseCephCommandSelector(%q) = %+v, want %+v", test.selector, got, test.want)
+			}
+		})
+	}
+}
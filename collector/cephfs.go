@@ -0,0 +1,83 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"syscall"
+
+	fsadmin "github.com/ceph/go-ceph/cephfs/admin"
+	"github.com/ceph/go-ceph/rados"
+)
+
+// cephfsNoGroup is the name of the default subvolume group (subvolumes created without a group).
+const cephfsNoGroup = "_nogroup"
+
+// cephfsVolumes returns the filesystems selected by the `cephfs.filesystems` config (all filesystems if empty).
+func cephfsVolumes(client *Client, fsa *fsadmin.FSAdmin) ([]string, error) {
+	if len(client.Config.CephFS.Filesystems) > 0 {
+		return client.Config.CephFS.Filesystems, nil
+	}
+
+	return fsa.ListVolumes()
+}
+
+// cephfsMountLost returns true when the error means that the CephFS client isn't usable anymore,
+// e.g., the client has been evicted (blocklisted) by the MDS or isn't connected to the cluster.
+func cephfsMountLost(err error) bool {
+	var cephErr interface{ ErrorCode() int }
+	if !errors.As(err, &cephErr) {
+		return false
+	}
+
+	switch syscall.Errno(-cephErr.ErrorCode()) {
+	case syscall.ENOTCONN, syscall.ESHUTDOWN:
+		return true
+	}
+
+	return false
+}
+
+// cephfsMgrCommand runs the mgr command and unmarshals the JSON output into out.
+func cephfsMgrCommand(conn *rados.Conn, args map[string]string, out any) error {
+	args["format"] = "json"
+	cmd, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	buf, status, err := conn.MgrCommand([][]byte{cmd})
+	if err != nil {
+		return fmt.Errorf("%w (status: %s)", err, status)
+	}
+
+	return json.Unmarshal(buf, out)
+}
+
+// cephfsQuotaBytes returns the quota in bytes, false if there is no quota (`infinite`).
+func cephfsQuotaBytes(quota any) (float64, bool) {
+	switch q := quota.(type) {
+	case fsadmin.ByteCount:
+		return float64(q), true
+	case float64:
+		return q, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/ceph/go-ceph/cephfs"
+	fsadmin "github.com/ceph/go-ceph/cephfs/admin"
+	"github.com/ceph/go-ceph/rados"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// cephfsClientMetadataLabels are the client metadata keys added as labels to the client info metric.
+var cephfsClientMetadataLabels = []string{
+	"hostname",
+	"root",
+	"kernel_version",
+	"ceph_version",
+	"entity_id",
+}
+
+// cephfsFSGet is the (partial) output of the `fs get` mon command.
+type cephfsFSGet struct {
+	MDSMap struct {
+		Info map[string]struct {
+			Name  string `json:"name"`
+			Rank  int64  `json:"rank"`
+			State string `json:"state"`
+		} `json:"info"`
+	} `json:"mdsmap"`
+}
+
+// cephfsDecayCounter is a decaying counter of the MDS session dump.
+type cephfsDecayCounter struct {
+	Value float64 `json:"value"`
+}
+
+// cephfsClientSession is a session of the MDS `session ls` command output.
+type cephfsClientSession struct {
+	ID                   int64              `json:"id"`
+	State                string             `json:"state"`
+	NumLeases            uint64             `json:"num_leases"`
+	NumCaps              uint64             `json:"num_caps"`
+	RequestLoadAvg       float64            `json:"request_load_avg"`
+	Uptime               float64            `json:"uptime"`
+	RequestsInFlight     uint64             `json:"requests_in_flight"`
+	NumCompletedRequests uint64             `json:"num_completed_requests"`
+	RecallCaps           cephfsDecayCounter `json:"recall_caps"`
+	ReleaseCaps          cephfsDecayCounter `json:"release_caps"`
+	// ClientMetadata values are strings, except for the client features and metric spec
+	ClientMetadata map[string]any `json:"client_metadata"`
+}
+
+type CephFSClients struct {
+	current *prometheus.Desc
+
+	mutex sync.Mutex
+	// Initialized (not mounted) CephFS clients to send the MDS commands with, by rados connection
+	mounts map[*rados.Conn]*cephfs.MountInfo
+}
+
+func init() {
+	Factories["cephfs_clients"] = NewCephFSClients
+}
+
+func NewCephFSClients() (Collector, error) {
+	return &CephFSClients{
+		mounts: map[*rados.Conn]*cephfs.MountInfo{},
+	}, nil
+}
+
+func (c *CephFSClients) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	mount, err := c.mount(client.Rados)
+	if err != nil {
+		return fmt.Errorf("failed to init cephfs client. %w", err)
+	}
+
+	volumes, err := cephfsVolumes(client, fsadmin.NewFromConn(client.Rados))
+	if err != nil {
+		return fmt.Errorf("failed to list cephfs volumes. %w", err)
+	}
+
+	var errs error
+	for _, volume := range volumes {
+		fs, err := cephfsGetFS(client.Rados, volume)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get mds map of %s volume. %w", volume, err))
+			continue
+		}
+
+		for _, mds := range fs.MDSMap.Info {
+			// Only the active MDS have client sessions
+			if mds.State != "up:active" {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return multierr.Append(errs, err)
+			}
+
+			sessions, err := cephfsSessions(mount, mds.Name)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to list sessions of mds %s (volume: %s). %w", mds.Name, volume, err))
+				if cephfsMountLost(err) {
+					c.release(client.Rados)
+					return errs
+				}
+				continue
+			}
+
+			for _, session := range sessions {
+				c.updateSession(client, volume, mds.Rank, session, ch)
+			}
+		}
+	}
+
+	return errs
+}
+
+func (c *CephFSClients) updateSession(client *Client, volume string, rank int64, session *cephfsClientSession, ch chan<- prometheus.Metric) {
+	// A client has a session with each active MDS rank
+	labels := map[string]string{
+		"fs":     volume,
+		"rank":   strconv.FormatInt(rank, 10),
+		"client": "client." + strconv.FormatInt(session.ID, 10),
+	}
+
+	stateLabels := map[string]string{
+		"state": session.State,
+	}
+	for k, v := range labels {
+		stateLabels[k] = v
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "client_session_state"),
+		"CephFS Client session state",
+		nil, stateLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	for metric, value := range map[string]struct {
+		help  string
+		value float64
+	}{
+		"client_caps":                   {"CephFS Client number of caps held", float64(session.NumCaps)},
+		"client_leases":                 {"CephFS Client number of leases held", float64(session.NumLeases)},
+		"client_requests_in_flight":     {"CephFS Client number of requests in flight", float64(session.RequestsInFlight)},
+		"client_completed_requests":     {"CephFS Client number of completed requests not yet trimmed by the client", float64(session.NumCompletedRequests)},
+		"client_request_load_avg":       {"CephFS Client request load average", session.RequestLoadAvg},
+		"client_recall_caps":            {"CephFS Client caps recalled by the MDS (decaying counter)", session.RecallCaps.Value},
+		"client_release_caps":           {"CephFS Client caps released by the client (decaying counter)", session.ReleaseCaps.Value},
+		"client_session_uptime_seconds": {"CephFS Client session uptime", session.Uptime},
+	} {
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", metric),
+			value.help,
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, value.value)
+	}
+
+	if !client.Config.CephFS.Clients.Metadata {
+		return
+	}
+
+	infoLabels := map[string]string{}
+	for _, key := range cephfsClientMetadataLabels {
+		// Not all keys are set by all clients, e.g., `kernel_version` is only set by the kernel client
+		value, _ := session.ClientMetadata[key].(string)
+		infoLabels[key] = value
+	}
+	for k, v := range labels {
+		infoLabels[k] = v
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "client_info"),
+		"CephFS Client info",
+		nil, infoLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+}
+
+// mount returns the (cached) CephFS client for the connection, which is initialized but not mounted.
+func (c *CephFSClients) mount(conn *rados.Conn) (*cephfs.MountInfo, error) {
+	if mount, ok := c.mounts[conn]; ok {
+		return mount, nil
+	}
+
+	mount, err := cephfs.CreateFromRados(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mount.Init(); err != nil {
+		mount.Release()
+		return nil, err
+	}
+
+	c.mounts[conn] = mount
+
+	return mount, nil
+}
+
+// release drops the cached CephFS client of the connection, so that it is initialized again with the next scrape.
+func (c *CephFSClients) release(conn *rados.Conn) {
+	mount, ok := c.mounts[conn]
+	if !ok {
+		return
+	}
+	delete(c.mounts, conn)
+
+	// The client is most likely not usable anymore, so errors are ignored
+	_ = mount.Release()
+}
+
+func cephfsGetFS(conn *rados.Conn, volume string) (*cephfsFSGet, error) {
+	cmd, err := json.Marshal(map[string]string{
+		"prefix":  "fs get",
+		"fs_name": volume,
+		"format":  "json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out, status, err := conn.MonCommand(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("%w (status: %s)", err, status)
+	}
+
+	fs := &cephfsFSGet{}
+	if err := json.Unmarshal(out, fs); err != nil {
+		return nil, err
+	}
+
+	return fs, nil
+}
+
+func cephfsSessions(mount *cephfs.MountInfo, mds string) ([]*cephfsClientSession, error) {
+	cmd, err := json.Marshal(map[string]string{
+		"prefix": "session ls",
+		"format": "json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out, status, err := mount.MdsCommand(mds, [][]byte{cmd})
+	if err != nil {
+		return nil, fmt.Errorf("%w (status: %s)", err, status)
+	}
+
+	sessions := []*cephfsClientSession{}
+	if err := json.Unmarshal(out, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ceph/go-ceph/cephfs"
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// cephfsDirStats are the recursive stats xattrs of a directory and their metric name.
+var cephfsDirStats = []struct {
+	xattr  string
+	metric string
+	help   string
+}{
+	{"ceph.dir.rbytes", "dir_rbytes", "CephFS Directory recursive bytes"},
+	{"ceph.dir.rfiles", "dir_rfiles", "CephFS Directory recursive number of files"},
+	{"ceph.dir.rsubdirs", "dir_rsubdirs", "CephFS Directory recursive number of subdirectories"},
+}
+
+type CephFSDirQuotas struct {
+	current *prometheus.Desc
+
+	mutex sync.Mutex
+	// Mounts by filesystem, kept open between scrapes
+	mounts map[string]*cephfs.MountInfo
+}
+
+func init() {
+	Factories["cephfs_dir_quotas"] = NewCephFSDirQuotas
+}
+
+func NewCephFSDirQuotas() (Collector, error) {
+	return &CephFSDirQuotas{
+		mounts: map[string]*cephfs.MountInfo{},
+	}, nil
+}
+
+func (c *CephFSDirQuotas) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Directories can be listed multiple times when discovered subdirectories overlap
+	done := map[string]bool{}
+
+	var errs error
+	for _, dirCfg := range client.Config.CephFS.Directories {
+		mount, err := c.mount(client.Config.CephFS, dirCfg.Filesystem)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to mount cephfs filesystem %q. %w", dirCfg.Filesystem, err))
+			continue
+		}
+
+		dirs, err := cephfsDirs(mount, dirCfg.Path, dirCfg.Depth)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to discover directories of %s (filesystem: %q). %w", dirCfg.Path, dirCfg.Filesystem, err))
+			if cephfsMountLost(err) {
+				c.unmount(dirCfg.Filesystem)
+				continue
+			}
+		}
+
+		for _, dir := range dirs {
+			if err := ctx.Err(); err != nil {
+				return multierr.Append(errs, err)
+			}
+
+			if done[dirCfg.Filesystem+":"+dir] {
+				continue
+			}
+			done[dirCfg.Filesystem+":"+dir] = true
+
+			if err := c.updateDir(mount, dirCfg.Filesystem, dir, ch); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to get stats of %s directory (filesystem: %q). %w", dir, dirCfg.Filesystem, err))
+				if cephfsMountLost(err) {
+					c.unmount(dirCfg.Filesystem)
+					break
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+func (c *CephFSDirQuotas) updateDir(mount *cephfs.MountInfo, fs string, dir string, ch chan<- prometheus.Metric) error {
+	labels := map[string]string{
+		"fs":   fs,
+		"path": dir,
+	}
+
+	stats := map[string]uint64{}
+	for _, stat := range cephfsDirStats {
+		value, err := cephfsGetXattrUint(mount, dir, stat.xattr)
+		if err != nil {
+			return fmt.Errorf("failed to get %s. %w", stat.xattr, err)
+		}
+		stats[stat.xattr] = value
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", stat.metric),
+			stat.help,
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(value))
+	}
+
+	// The quota xattrs aren't available (or 0) when no quota is set
+	if maxBytes, err := cephfsGetXattrUint(mount, dir, "ceph.quota.max_bytes"); err == nil && maxBytes > 0 {
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", "dir_quota_max_bytes"),
+			"CephFS Directory quota max bytes",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(maxBytes))
+
+		c.updateQuotaUsedRatio("bytes", maxBytes, stats["ceph.dir.rbytes"], labels, ch)
+	}
+
+	if maxFiles, err := cephfsGetXattrUint(mount, dir, "ceph.quota.max_files"); err == nil && maxFiles > 0 {
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", "dir_quota_max_files"),
+			"CephFS Directory quota max files",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(maxFiles))
+
+		// The files quota is checked against the files and subdirectories
+		c.updateQuotaUsedRatio("files", maxFiles, stats["ceph.dir.rfiles"]+stats["ceph.dir.rsubdirs"], labels, ch)
+	}
+
+	return nil
+}
+
+func (c *CephFSDirQuotas) updateQuotaUsedRatio(quotaType string, max uint64, used uint64, labels map[string]string, ch chan<- prometheus.Metric) {
+	quotaLabels := map[string]string{
+		"quota": quotaType,
+	}
+	for k, v := range labels {
+		quotaLabels[k] = v
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "dir_quota_used_ratio"),
+		"CephFS Directory quota used ratio",
+		nil, quotaLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(used)/float64(max))
+}
+
+// mount returns the (cached) mount of the filesystem, the default filesystem is used if empty.
+func (c *CephFSDirQuotas) mount(cfg config.CephFS, fs string) (*cephfs.MountInfo, error) {
+	if mount, ok := c.mounts[fs]; ok {
+		return mount, nil
+	}
+
+	mount, err := cephfs.CreateMount()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.setupMount(mount, cfg, fs); err != nil {
+		mount.Release()
+		return nil, err
+	}
+
+	c.mounts[fs] = mount
+
+	return mount, nil
+}
+
+// unmount drops the cached mount of the filesystem, so that it is mounted again with the next scrape.
+func (c *CephFSDirQuotas) unmount(fs string) {
+	mount, ok := c.mounts[fs]
+	if !ok {
+		return
+	}
+	delete(c.mounts, fs)
+
+	// The mount is most likely not usable anymore, so errors are ignored
+	_ = mount.Unmount()
+	_ = mount.Release()
+}
+
+func (c *CephFSDirQuotas) setupMount(mount *cephfs.MountInfo, cfg config.CephFS, fs string) error {
+	if cfg.CephConfig != "" {
+		if err := mount.ReadConfigFile(cfg.CephConfig); err != nil {
+			return fmt.Errorf("failed to read custom ceph config file %s. %w", cfg.CephConfig, err)
+		}
+	} else {
+		if err := mount.ReadDefaultConfigFile(); err != nil {
+			return fmt.Errorf("failed to read default ceph config file. %w", err)
+		}
+	}
+
+	if fs != "" {
+		if err := mount.SelectFilesystem(fs); err != nil {
+			return err
+		}
+	}
+
+	return mount.Mount()
+}
+
+// cephfsDirs returns the directory and its subdirectories up to the given depth.
+func cephfsDirs(mount *cephfs.MountInfo, dir string, depth int) ([]string, error) {
+	dirs := []string{dir}
+	if depth <= 0 {
+		return dirs, nil
+	}
+
+	d, err := mount.OpenDir(dir)
+	if err != nil {
+		return dirs, err
+	}
+	defer d.Close()
+
+	var errs error
+	for {
+		entry, err := d.ReadDir()
+		if err != nil {
+			return dirs, multierr.Append(errs, err)
+		}
+		if entry == nil {
+			break
+		}
+
+		if entry.DType() != cephfs.DTypeDir || entry.Name() == "." || entry.Name() == ".." {
+			continue
+		}
+
+		subDirs, err := cephfsDirs(mount, path.Join(dir, entry.Name()), depth-1)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+		}
+		dirs = append(dirs, subDirs...)
+	}
+
+	return dirs, errs
+}
+
+func cephfsGetXattrUint(mount *cephfs.MountInfo, dir string, name string) (uint64, error) {
+	value, err := mount.GetXattr(dir, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(string(value)), 10, 64)
+}
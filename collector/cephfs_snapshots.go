@@ -0,0 +1,326 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	fsadmin "github.com/ceph/go-ceph/cephfs/admin"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// cephfsSnapScheduleTimeLayouts are the timestamp formats used by the snap_schedule mgr module.
+var cephfsSnapScheduleTimeLayouts = []string{
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// cephfsSnapSchedule is a schedule of the `fs snap-schedule status` mgr command output.
+type cephfsSnapSchedule struct {
+	FS        string           `json:"fs"`
+	Subvol    string           `json:"subvol"`
+	Group     string           `json:"group"`
+	Path      string           `json:"path"`
+	Schedule  string           `json:"schedule"`
+	Retention map[string]int64 `json:"retention"`
+	Start     string           `json:"start"`
+	Created   string           `json:"created"`
+	First     string           `json:"first"`
+	Last      string           `json:"last"`
+	// LastPruned is the time snapshots have last been pruned by the retention
+	LastPruned   string `json:"last_pruned"`
+	CreatedCount uint64 `json:"created_count"`
+	PrunedCount  uint64 `json:"pruned_count"`
+	// Active is a bool (or 0/1 with older Ceph versions)
+	Active any `json:"active"`
+}
+
+type CephFSSnapshots struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["cephfs_snapshots"] = NewCephFSSnapshots
+}
+
+func NewCephFSSnapshots() (Collector, error) {
+	return &CephFSSnapshots{}, nil
+}
+
+func (c *CephFSSnapshots) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	fsa := fsadmin.NewFromConn(client.Rados)
+
+	volumes, err := cephfsVolumes(client, fsa)
+	if err != nil {
+		return fmt.Errorf("failed to list cephfs volumes. %w", err)
+	}
+
+	var errs error
+	for _, volume := range volumes {
+		groups, err := fsa.ListSubVolumeGroups(volume)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to list subvolume groups of %s volume. %w", volume, err))
+			continue
+		}
+
+		paths := append([]string{}, client.Config.CephFS.SnapshotSchedulePaths...)
+
+		// Subvolumes created without a group are in the default group
+		for _, group := range append(groups, "") {
+			if err := ctx.Err(); err != nil {
+				return multierr.Append(errs, err)
+			}
+
+			subvolumes, err := fsa.ListSubVolumes(volume, group)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to list subvolumes of %s volume (group: %s). %w", volume, group, err))
+				continue
+			}
+
+			for _, subvolume := range subvolumes {
+				if err := c.updateSubVolume(fsa, volume, group, subvolume, ch); err != nil {
+					errs = multierr.Append(errs, err)
+				}
+
+				path, err := fsa.SubVolumePath(volume, group, subvolume)
+				if err != nil {
+					errs = multierr.Append(errs, fmt.Errorf("failed to get subvolume %s path of %s volume (group: %s). %w", subvolume, volume, group, err))
+					continue
+				}
+				paths = append(paths, path)
+			}
+		}
+
+		// A schedule could be returned for multiple paths, e.g., when a subvolume path is configured as well
+		done := map[string]bool{}
+		for _, path := range paths {
+			if err := ctx.Err(); err != nil {
+				return multierr.Append(errs, err)
+			}
+
+			schedules := []*cephfsSnapSchedule{}
+			if err := cephfsMgrCommand(client.Rados, map[string]string{
+				"prefix": "fs snap-schedule status",
+				"path":   path,
+				"fs":     volume,
+			}, &schedules); err != nil {
+				// Without the snap_schedule mgr module there are no schedules
+				if cephfsSnapScheduleUnavailable(err) {
+					break
+				}
+				errs = multierr.Append(errs, fmt.Errorf("failed to get snapshot schedule status of %s path of %s volume. %w", path, volume, err))
+				continue
+			}
+
+			for _, schedule := range schedules {
+				if done[schedule.Path+":"+schedule.Schedule] {
+					continue
+				}
+				done[schedule.Path+":"+schedule.Schedule] = true
+
+				c.updateSchedule(volume, schedule, ch)
+			}
+		}
+	}
+
+	return errs
+}
+
+func (c *CephFSSnapshots) updateSubVolume(fsa *fsadmin.FSAdmin, volume string, group string, subvolume string, ch chan<- prometheus.Metric) error {
+	snapshots, err := fsa.ListSubVolumeSnapshots(volume, group, subvolume)
+	if err != nil {
+		return fmt.Errorf("failed to list subvolume %s snapshots of %s volume (group: %s). %w", subvolume, volume, group, err)
+	}
+
+	var errs error
+	var oldest, newest time.Time
+	for _, snapshot := range snapshots {
+		info, err := fsa.SubVolumeSnapshotInfo(volume, group, subvolume, snapshot)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get subvolume %s snapshot %s info of %s volume (group: %s). %w", subvolume, snapshot, volume, group, err))
+			continue
+		}
+
+		if info.CreatedAt.IsZero() {
+			continue
+		}
+		if oldest.IsZero() || info.CreatedAt.Before(oldest) {
+			oldest = info.CreatedAt.Time
+		}
+		if newest.IsZero() || info.CreatedAt.After(newest) {
+			newest = info.CreatedAt.Time
+		}
+	}
+
+	if group == "" {
+		group = cephfsNoGroup
+	}
+
+	labels := map[string]string{
+		"fs":        volume,
+		"group":     group,
+		"subvolume": subvolume,
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "subvolume_snapshots"),
+		"CephFS Subvolume number of snapshots",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(len(snapshots)))
+
+	if !oldest.IsZero() {
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", "subvolume_snapshot_oldest_timestamp_seconds"),
+			"CephFS Subvolume creation timestamp of the oldest snapshot",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(oldest.Unix()))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", "subvolume_snapshot_newest_timestamp_seconds"),
+			"CephFS Subvolume creation timestamp of the newest snapshot",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(newest.Unix()))
+	}
+
+	return errs
+}
+
+func (c *CephFSSnapshots) updateSchedule(volume string, schedule *cephfsSnapSchedule, ch chan<- prometheus.Metric) {
+	labels := map[string]string{
+		"fs":       volume,
+		"path":     schedule.Path,
+		"schedule": schedule.Schedule,
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "snap_schedule_info"),
+		"CephFS Snapshot schedule info",
+		nil, map[string]string{
+			"fs":        volume,
+			"path":      schedule.Path,
+			"schedule":  schedule.Schedule,
+			"start":     schedule.Start,
+			"subvolume": schedule.Subvol,
+			"group":     schedule.Group,
+		})
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	active := 0.0
+	switch a := schedule.Active.(type) {
+	case bool:
+		if a {
+			active = 1
+		}
+	case float64:
+		active = a
+	}
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "snap_schedule_active"),
+		"CephFS Snapshot schedule active (1 = active, 0 = inactive)",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, active)
+
+	for period, count := range schedule.Retention {
+		retentionLabels := map[string]string{
+			"period": period,
+		}
+		for k, v := range labels {
+			retentionLabels[k] = v
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", "snap_schedule_retention"),
+			"CephFS Snapshot schedule number of snapshots to keep per retention period",
+			nil, retentionLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(count))
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "snap_schedule_created_snapshots_total"),
+		"CephFS Snapshot schedule number of created snapshots",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.CounterValue, float64(schedule.CreatedCount))
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "snap_schedule_pruned_snapshots_total"),
+		"CephFS Snapshot schedule number of pruned snapshots",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.CounterValue, float64(schedule.PrunedCount))
+
+	for metric, value := range map[string]struct {
+		help  string
+		value string
+	}{
+		"snap_schedule_first_snapshot_timestamp_seconds": {"CephFS Snapshot schedule time the first snapshot has been created at", schedule.First},
+		"snap_schedule_last_snapshot_timestamp_seconds":  {"CephFS Snapshot schedule time the last snapshot has been created at", schedule.Last},
+		"snap_schedule_last_pruned_timestamp_seconds":    {"CephFS Snapshot schedule time snapshots have last been pruned at", schedule.LastPruned},
+	} {
+		// Not set until the schedule created (or pruned) its first snapshot
+		ts, ok := cephfsParseSnapScheduleTime(value.value)
+		if !ok {
+			continue
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", metric),
+			value.help,
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(ts.Unix()))
+	}
+}
+
+// cephfsParseSnapScheduleTime parses a snap_schedule timestamp (UTC), false if it is empty or invalid.
+func cephfsParseSnapScheduleTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range cephfsSnapScheduleTimeLayouts {
+		if ts, err := time.Parse(layout, value); err == nil {
+			return ts, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// cephfsSnapScheduleUnavailable returns true when the snap_schedule mgr module isn't enabled.
+func cephfsSnapScheduleUnavailable(err error) bool {
+	var cephErr interface{ ErrorCode() int }
+	if errors.As(err, &cephErr) && syscall.Errno(-cephErr.ErrorCode()) == syscall.ENOTSUP {
+		return true
+	}
+
+	// The mgr status contains the reason, e.g., "Module 'snap_schedule' is not enabled/loaded"
+	return strings.Contains(err.Error(), "not enabled")
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	fsadmin "github.com/ceph/go-ceph/cephfs/admin"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// cephfsSubVolumeGroupInfo is the output of the `fs subvolumegroup info` mgr command,
+// the go-ceph `SubVolumeGroupInfo` requires the `ceph_preview` build tag.
+type cephfsSubVolumeGroupInfo struct {
+	BytesUsed  uint64            `json:"bytes_used"`
+	BytesQuota any               `json:"bytes_quota"`
+	DataPool   string            `json:"data_pool"`
+	CreatedAt  fsadmin.TimeStamp `json:"created_at"`
+}
+
+type CephFSSubVolumes struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["cephfs_subvolumes"] = NewCephFSSubVolumes
+}
+
+func NewCephFSSubVolumes() (Collector, error) {
+	return &CephFSSubVolumes{}, nil
+}
+
+func (c *CephFSSubVolumes) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	fsa := fsadmin.NewFromConn(client.Rados)
+
+	volumes, err := cephfsVolumes(client, fsa)
+	if err != nil {
+		return fmt.Errorf("failed to list cephfs volumes. %w", err)
+	}
+
+	var errs error
+	for _, volume := range volumes {
+		groups, err := fsa.ListSubVolumeGroups(volume)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to list subvolume groups of %s volume. %w", volume, err))
+			continue
+		}
+
+		for _, group := range groups {
+			if err := c.updateGroup(client, volume, group, ch); err != nil {
+				errs = multierr.Append(errs, err)
+			}
+		}
+
+		// Subvolumes created without a group are in the default group
+		for _, group := range append(groups, "") {
+			if err := ctx.Err(); err != nil {
+				return multierr.Append(errs, err)
+			}
+
+			subvolumes, err := fsa.ListSubVolumes(volume, group)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to list subvolumes of %s volume (group: %s). %w", volume, group, err))
+				continue
+			}
+
+			for _, subvolume := range subvolumes {
+				if err := c.updateSubVolume(fsa, volume, group, subvolume, ch); err != nil {
+					errs = multierr.Append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+func (c *CephFSSubVolumes) updateGroup(client *Client, volume string, group string, ch chan<- prometheus.Metric) error {
+	info := &cephfsSubVolumeGroupInfo{}
+	if err := cephfsMgrCommand(client.Rados, map[string]string{
+		"prefix":     "fs subvolumegroup info",
+		"vol_name":   volume,
+		"group_name": group,
+	}, info); err != nil {
+		return fmt.Errorf("failed to get subvolume group %s info of %s volume. %w", group, volume, err)
+	}
+
+	labels := map[string]string{
+		"fs":    volume,
+		"group": group,
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "subvolume_group_info"),
+		"CephFS Subvolume group info",
+		nil, map[string]string{
+			"fs":        volume,
+			"group":     group,
+			"data_pool": info.DataPool,
+		})
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "subvolume_group_used_bytes"),
+		"CephFS Subvolume group used bytes",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(info.BytesUsed))
+
+	c.updateQuota("subvolume_group", "CephFS Subvolume group", info.BytesQuota, float64(info.BytesUsed), labels, ch)
+
+	if !info.CreatedAt.IsZero() {
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", "subvolume_group_created_timestamp_seconds"),
+			"CephFS Subvolume group creation timestamp",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(info.CreatedAt.Unix()))
+	}
+
+	return nil
+}
+
+func (c *CephFSSubVolumes) updateSubVolume(fsa *fsadmin.FSAdmin, volume string, group string, subvolume string, ch chan<- prometheus.Metric) error {
+	info, err := fsa.SubVolumeInfo(volume, group, subvolume)
+	if err != nil {
+		return fmt.Errorf("failed to get subvolume %s info of %s volume (group: %s). %w", subvolume, volume, group, err)
+	}
+
+	if group == "" {
+		group = cephfsNoGroup
+	}
+
+	labels := map[string]string{
+		"fs":        volume,
+		"group":     group,
+		"subvolume": subvolume,
+	}
+
+	infoLabels := map[string]string{
+		"type":           info.Type,
+		"state":          string(info.State),
+		"path":           info.Path,
+		"data_pool":      info.DataPool,
+		"pool_namespace": info.PoolNamespace,
+	}
+	for k, v := range labels {
+		infoLabels[k] = v
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "subvolume_info"),
+		"CephFS Subvolume info",
+		nil, infoLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", "subvolume_used_bytes"),
+		"CephFS Subvolume used bytes",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(info.BytesUsed))
+
+	c.updateQuota("subvolume", "CephFS Subvolume", info.BytesQuota, float64(info.BytesUsed), labels, ch)
+
+	if !info.CreatedAt.IsZero() {
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "cephfs", "subvolume_created_timestamp_seconds"),
+			"CephFS Subvolume creation timestamp",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(info.CreatedAt.Unix()))
+	}
+
+	return nil
+}
+
+// updateQuota emits the quota and its used ratio, nothing is emitted when there is no quota.
+func (c *CephFSSubVolumes) updateQuota(kind string, help string, quota any, used float64, labels map[string]string, ch chan<- prometheus.Metric) {
+	quotaBytes, ok := cephfsQuotaBytes(quota)
+	if !ok {
+		return
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", kind+"_quota_bytes"),
+		help+" quota bytes",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, quotaBytes)
+
+	if quotaBytes <= 0 {
+		return
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "cephfs", kind+"_quota_used_ratio"),
+		help+" quota used ratio",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, used/quotaBytes)
+}
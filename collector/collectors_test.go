@@ -0,0 +1,99 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// testCollector runs an update function as a prometheus collector, so the metrics can be gathered by a registry.
+type testCollector struct {
+	update func(ch chan<- prometheus.Metric) error
+	err    error
+}
+
+func (c *testCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c *testCollector) Collect(ch chan<- prometheus.Metric) {
+	c.err = c.update(ch)
+}
+
+// gatherMetrics runs the update function and returns the gathered metric values by name and labels,
+// e.g., `ceph_rgw_sync_data_shards{realm="test",zone="b"}`, and the error returned by the update function.
+func gatherMetrics(t *testing.T, update func(ch chan<- prometheus.Metric) error) (map[string]float64, error) {
+	t.Helper()
+
+	c := &testCollector{
+		update: update,
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(c); err != nil {
+		t.Fatalf("failed to register test collector. %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics. %v", err)
+	}
+
+	metrics := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			// Labels are sorted by name by the registry
+			labels := []string{}
+			for _, label := range m.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%s=%q", label.GetName(), label.GetValue()))
+			}
+
+			value := m.GetGauge().GetValue()
+			if m.GetCounter() != nil {
+				value = m.GetCounter().GetValue()
+			}
+
+			metrics[family.GetName()+"{"+strings.Join(labels, ",")+"}"] = value
+		}
+	}
+
+	return metrics, c.err
+}
+
+// compareMetrics reports the missing, unexpected and differing metrics.
+func compareMetrics(t *testing.T, want map[string]float64, got map[string]float64) {
+	t.Helper()
+
+	for name, value := range want {
+		gotValue, ok := got[name]
+		if !ok {
+			t.Errorf("missing metric %s", name)
+			continue
+		}
+		if gotValue != value {
+			t.Errorf("metric %s = %v, want %v", name, gotValue, value)
+		}
+	}
+
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			t.Errorf("unexpected metric %s = %v", name, got[name])
+		}
+	}
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// radosNamespaceUsage is the number of objects and the sampled object sizes of a namespace.
+type radosNamespaceUsage struct {
+	Objects      uint64
+	Sampled      uint64
+	SampledBytes uint64
+}
+
+// radosNamespaceScan is the state of the object listing of a pool, which is spread over multiple scrapes.
+type radosNamespaceScan struct {
+	// Token is the position (placement group hash) the listing continues at
+	Token rados.IterToken
+	// PGListed is the number of objects of the placement group at the token that have been counted already,
+	// they are skipped when the listing is continued
+	PGListed uint64
+	// PGUsage is the usage of the namespaces of the placement group at the token counted so far
+	PGUsage map[string]*radosNamespaceUsage
+	// Usage of the namespaces of the current listing pass (without the current placement group)
+	Usage map[string]*radosNamespaceUsage
+	// Usage of the namespaces of the last completed listing pass
+	Last map[string]*radosNamespaceUsage
+	// LastCompleted is the time the last listing pass has been completed at
+	LastCompleted time.Time
+}
+
+// radosObjectIter iterates over the objects of a pool, implemented by `rados.Iter`.
+type radosObjectIter interface {
+	Next() bool
+	Token() rados.IterToken
+	Namespace() string
+	Value() string
+	Err() error
+}
+
+type RADOSNamespaces struct {
+	current *prometheus.Desc
+
+	mutex sync.Mutex
+	scans map[string]*radosNamespaceScan
+}
+
+func init() {
+	Factories["rados_namespaces"] = NewRADOSNamespaces
+}
+
+func NewRADOSNamespaces() (Collector, error) {
+	return &RADOSNamespaces{
+		scans: map[string]*radosNamespaceScan{},
+	}, nil
+}
+
+func (c *RADOSNamespaces) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	// The scan state is shared, so only one listing can be in progress
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var errs error
+	// Listing all objects is expensive, so only the configured pools are used
+	for _, poolCfg := range client.Config.RBD.Pools {
+		if err := ctx.Err(); err != nil {
+			return multierr.Append(errs, err)
+		}
+
+		scan, ok := c.scans[poolCfg.Name]
+		if !ok {
+			scan = &radosNamespaceScan{
+				PGUsage: map[string]*radosNamespaceUsage{},
+				Usage:   map[string]*radosNamespaceUsage{},
+			}
+			c.scans[poolCfg.Name] = scan
+		}
+
+		if err := c.scan(ctx, client, poolCfg.Name, scan); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to list objects of %s pool. %w", poolCfg.Name, err))
+		}
+
+		c.updatePool(client.Config.RADOS.Namespaces, poolCfg, scan, ch)
+	}
+
+	return errs
+}
+
+func (c *RADOSNamespaces) updatePool(cfg config.RADOSNamespaces, poolCfg *config.RBDPool, scan *radosNamespaceScan, ch chan<- prometheus.Metric) {
+	poolLabels := map[string]string{
+		"pool": poolCfg.Name,
+	}
+
+	var listed uint64
+	for _, usage := range scan.Usage {
+		listed += usage.Objects
+	}
+	for _, usage := range scan.PGUsage {
+		listed += usage.Objects
+	}
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rados", "namespace_scan_listed_objects"),
+		"RADOS Namespace number of objects listed in the current listing pass",
+		nil, poolLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(listed))
+
+	// Nothing to report until the first listing pass is complete
+	if scan.Last == nil {
+		return
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rados", "namespace_scan_last_completed_timestamp_seconds"),
+		"RADOS Namespace time the last listing pass has been completed at",
+		nil, poolLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(scan.LastCompleted.Unix()))
+
+	for namespace, usage := range scan.Last {
+		if len(poolCfg.Namespaces) > 0 && !slices.Contains(poolCfg.Namespaces, namespace) {
+			continue
+		}
+
+		labels := map[string]string{
+			"pool":      poolCfg.Name,
+			"namespace": namespace,
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rados", "namespace_objects"),
+			"RADOS Namespace number of objects",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(usage.Objects))
+
+		if cfg.StatSampleRate <= 0 || usage.Sampled == 0 {
+			continue
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rados", "namespace_bytes"),
+			"RADOS Namespace bytes (estimated from the sampled objects)",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(usage.SampledBytes)/float64(usage.Sampled)*float64(usage.Objects))
+	}
+}
+
+// scan continues the object listing of the pool until the budget is used up.
+func (c *RADOSNamespaces) scan(ctx context.Context, client *Client, pool string, scan *radosNamespaceScan) error {
+	ioctx, err := client.Rados.OpenIOContext(pool)
+	if err != nil {
+		return err
+	}
+	defer ioctx.Destroy()
+	ioctx.SetNamespace(rados.AllNamespaces)
+
+	// The namespace of the listing IO context can't be changed for the stat calls
+	statIoctx, err := client.Rados.OpenIOContext(pool)
+	if err != nil {
+		return err
+	}
+	defer statIoctx.Destroy()
+
+	iter, err := ioctx.Iter()
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	iter.Seek(scan.Token)
+
+	return radosScanObjects(ctx, client.Config.RADOS.Namespaces, iter, func(namespace string, oid string) (uint64, error) {
+		statIoctx.SetNamespace(namespace)
+		stat, err := statIoctx.Stat(oid)
+		if err != nil {
+			return 0, err
+		}
+		return stat.Size, nil
+	}, scan)
+}
+
+// radosScanObjects counts the objects of the iterator (positioned at the scan's token) per namespace until the
+// budget is used up. The listing can be stopped within a placement group, the objects of the placement group
+// counted already are skipped when the listing is continued with the next scrape.
+func radosScanObjects(ctx context.Context, cfg config.RADOSNamespaces, iter radosObjectIter, stat func(namespace string, oid string) (uint64, error), scan *radosNamespaceScan) error {
+	start := time.Now()
+	listed := 0
+	skip := scan.PGListed
+	for iter.Next() {
+		if token := iter.Token(); token != scan.Token {
+			radosMergeNamespaceUsage(scan.Usage, scan.PGUsage)
+			scan.PGUsage = map[string]*radosNamespaceUsage{}
+			scan.PGListed = 0
+			scan.Token = token
+			skip = 0
+		}
+
+		// Objects are listed in the same order within a placement group, as long as it doesn't change
+		if skip > 0 {
+			skip--
+			continue
+		}
+
+		if cfg.MaxObjectsPerScrape > 0 && listed >= cfg.MaxObjectsPerScrape {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		namespace := iter.Namespace()
+		usage, ok := scan.PGUsage[namespace]
+		if !ok {
+			usage = &radosNamespaceUsage{}
+			scan.PGUsage[namespace] = usage
+		}
+		usage.Objects++
+		scan.PGListed++
+		listed++
+
+		if cfg.StatSampleRate > 0 && listed%cfg.StatSampleRate == 0 {
+			size, err := stat(namespace, iter.Value())
+			if err != nil {
+				// The object might have been removed since it has been listed
+				if !errors.Is(err, rados.ErrNotFound) {
+					return err
+				}
+			} else {
+				usage.Sampled++
+				usage.SampledBytes += size
+			}
+		}
+
+		if cfg.ObjectsPerSecond > 0 {
+			wait := time.Until(start.Add(time.Duration(listed) * time.Second / time.Duration(cfg.ObjectsPerSecond)))
+			if wait > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	// The listing pass is complete, start over with the next scrape
+	radosMergeNamespaceUsage(scan.Usage, scan.PGUsage)
+	scan.Last = scan.Usage
+	scan.Usage = map[string]*radosNamespaceUsage{}
+	scan.PGUsage = map[string]*radosNamespaceUsage{}
+	scan.PGListed = 0
+	scan.Token = 0
+	scan.LastCompleted = time.Now()
+
+	return nil
+}
+
+func radosMergeNamespaceUsage(dst map[string]*radosNamespaceUsage, src map[string]*radosNamespaceUsage) {
+	for namespace, usage := range src {
+		d, ok := dst[namespace]
+		if !ok {
+			d = &radosNamespaceUsage{}
+			dst[namespace] = d
+		}
+		d.Objects += usage.Objects
+		d.Sampled += usage.Sampled
+		d.SampledBytes += usage.SampledBytes
+	}
+}
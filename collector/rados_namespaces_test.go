@@ -0,0 +1,188 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+)
+
+type fakeRadosObject struct {
+	token     rados.IterToken
+	namespace string
+	oid       string
+}
+
+// fakeRadosIter lists the objects starting at the first object of the placement group at the given token.
+type fakeRadosIter struct {
+	objects []fakeRadosObject
+	pos     int
+}
+
+func newFakeRadosIter(objects []fakeRadosObject, token rados.IterToken) *fakeRadosIter {
+	pos := 0
+	for pos < len(objects) && objects[pos].token < token {
+		pos++
+	}
+
+	return &fakeRadosIter{
+		objects: objects,
+		// Next moves to the first object
+		pos: pos - 1,
+	}
+}
+
+func (i *fakeRadosIter) Next() bool {
+	i.pos++
+	return i.pos < len(i.objects)
+}
+
+func (i *fakeRadosIter) Token() rados.IterToken {
+	return i.objects[i.pos].token
+}
+
+func (i *fakeRadosIter) Namespace() string {
+	return i.objects[i.pos].namespace
+}
+
+func (i *fakeRadosIter) Value() string {
+	return i.objects[i.pos].oid
+}
+
+func (i *fakeRadosIter) Err() error {
+	return nil
+}
+
+func TestRADOSScanObjects(t *testing.T) {
+	// The first placement group has more objects than the budget of a scrape
+	objects := []fakeRadosObject{}
+	for i := 0; i < 25; i++ {
+		namespace := "a"
+		if i%5 == 0 {
+			namespace = "b"
+		}
+		objects = append(objects, fakeRadosObject{token: 0, namespace: namespace, oid: fmt.Sprintf("obj-%d", i)})
+	}
+	for i := 25; i < 30; i++ {
+		objects = append(objects, fakeRadosObject{token: 1, namespace: "a", oid: fmt.Sprintf("obj-%d", i)})
+	}
+
+	tests := []struct {
+		name        string
+		maxObjects  int
+		wantScrapes int
+	}{
+		{name: "unlimited", maxObjects: 0, wantScrapes: 1},
+		{name: "one object per scrape", maxObjects: 1, wantScrapes: 30},
+		{name: "within placement group", maxObjects: 7, wantScrapes: 5},
+		{name: "placement group boundary", maxObjects: 25, wantScrapes: 2},
+		{name: "all objects", maxObjects: 30, wantScrapes: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := config.RADOSNamespaces{
+				MaxObjectsPerScrape: test.maxObjects,
+				StatSampleRate:      2,
+			}
+
+			scan := &radosNamespaceScan{
+				PGUsage: map[string]*radosNamespaceUsage{},
+				Usage:   map[string]*radosNamespaceUsage{},
+			}
+
+			stat := func(namespace string, oid string) (uint64, error) {
+				return 100, nil
+			}
+
+			scrapes := 0
+			for scan.Last == nil {
+				if scrapes >= len(objects)+1 {
+					t.Fatalf("listing pass not completed after %d scrapes", scrapes)
+				}
+				scrapes++
+
+				if err := radosScanObjects(context.Background(), cfg, newFakeRadosIter(objects, scan.Token), stat, scan); err != nil {
+					t.Fatalf("unexpected error. %v", err)
+				}
+			}
+
+			if scrapes != test.wantScrapes {
+				t.Errorf("listing pass completed after %d scrapes, want %d", scrapes, test.wantScrapes)
+			}
+
+			for namespace, want := range map[string]uint64{"a": 25, "b": 5} {
+				usage, ok := scan.Last[namespace]
+				if !ok {
+					t.Errorf("missing usage of namespace %s", namespace)
+					continue
+				}
+				if usage.Objects != want {
+					t.Errorf("namespace %s objects = %d, want %d", namespace, usage.Objects, want)
+				}
+				if usage.Sampled > 0 && usage.SampledBytes/usage.Sampled != 100 {
+					t.Errorf("namespace %s sampled bytes per object = %d, want 100", namespace, usage.SampledBytes/usage.Sampled)
+				}
+			}
+
+			if scan.Token != 0 || scan.PGListed != 0 || len(scan.PGUsage) != 0 || len(scan.Usage) != 0 {
+				t.Errorf("expected scan state to be reset after the listing pass, got %+v", scan)
+			}
+		})
+	}
+}
+
+func TestRADOSScanObjectsCanceled(t *testing.T) {
+	objects := []fakeRadosObject{}
+	for i := 0; i < 10; i++ {
+		objects = append(objects, fakeRadosObject{token: 0, namespace: "a", oid: fmt.Sprintf("obj-%d", i)})
+	}
+
+	scan := &radosNamespaceScan{
+		PGUsage: map[string]*radosNamespaceUsage{},
+		Usage:   map[string]*radosNamespaceUsage{},
+	}
+
+	cfg := config.RADOSNamespaces{
+		MaxObjectsPerScrape: 4,
+	}
+	if err := radosScanObjects(context.Background(), cfg, newFakeRadosIter(objects, scan.Token), nil, scan); err != nil {
+		t.Fatalf("unexpected error. %v", err)
+	}
+
+	// The progress within the placement group is kept when the scrape is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := radosScanObjects(ctx, cfg, newFakeRadosIter(objects, scan.Token), nil, scan); err == nil {
+		t.Fatalf("expected context canceled error")
+	}
+	if scan.PGListed != 4 || scan.PGUsage["a"].Objects != 4 {
+		t.Errorf("expected 4 objects to be counted, got %d (usage: %d)", scan.PGListed, scan.PGUsage["a"].Objects)
+	}
+
+	cfg.MaxObjectsPerScrape = 0
+	if err := radosScanObjects(context.Background(), cfg, newFakeRadosIter(objects, scan.Token), nil, scan); err != nil {
+		t.Fatalf("unexpected error. %v", err)
+	}
+	if scan.Last == nil || scan.Last["a"].Objects != 10 {
+		t.Errorf("expected 10 objects in the completed listing pass, got %+v", scan.Last)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// radosPoolQuota is the output of the `osd pool get-quota` mon command.
+type radosPoolQuota struct {
+	PoolName        string `json:"pool_name"`
+	PoolID          int64  `json:"pool_id"`
+	QuotaMaxObjects uint64 `json:"quota_max_objects"`
+	QuotaMaxBytes   uint64 `json:"quota_max_bytes"`
+}
+
+type RADOSPools struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rados_pools"] = NewRADOSPools
+}
+
+func NewRADOSPools() (Collector, error) {
+	return &RADOSPools{}, nil
+}
+
+func (c *RADOSPools) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	pools, err := client.Rados.ListPools()
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, pool := range pools {
+		if err := ctx.Err(); err != nil {
+			return multierr.Append(errs, err)
+		}
+
+		labels := map[string]string{
+			"pool": pool,
+		}
+
+		stats, err := c.poolStats(client.Rados, pool)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get stats of %s pool. %w", pool, err))
+			continue
+		}
+
+		for metric, value := range map[string]struct {
+			help  string
+			value uint64
+		}{
+			"pool_objects":                    {"RADOS Pool number of objects", stats.Num_objects},
+			"pool_bytes":                      {"RADOS Pool stored bytes", stats.Num_bytes},
+			"pool_object_clones":              {"RADOS Pool number of object clones", stats.Num_object_clones},
+			"pool_object_copies":              {"RADOS Pool number of object copies (objects * replicas)", stats.Num_object_copies},
+			"pool_objects_degraded":           {"RADOS Pool number of degraded objects", stats.Num_objects_degraded},
+			"pool_objects_unfound":            {"RADOS Pool number of unfound objects", stats.Num_objects_unfound},
+			"pool_objects_missing_on_primary": {"RADOS Pool number of objects missing on primary", stats.Num_objects_missing_on_primary},
+		} {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rados", metric),
+				value.help,
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(value.value))
+		}
+
+		for metric, value := range map[string]struct {
+			help  string
+			value uint64
+		}{
+			"pool_read_ops_total":    {"RADOS Pool read operations", stats.Num_rd},
+			"pool_read_bytes_total":  {"RADOS Pool read bytes", stats.Num_rd_kb * 1024},
+			"pool_write_ops_total":   {"RADOS Pool write operations", stats.Num_wr},
+			"pool_write_bytes_total": {"RADOS Pool written bytes", stats.Num_wr_kb * 1024},
+		} {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rados", metric),
+				value.help,
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.CounterValue, float64(value.value))
+		}
+
+		quota, err := radosGetPoolQuota(client.Rados, pool)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get quota of %s pool. %w", pool, err))
+			continue
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rados", "pool_quota_max_bytes"),
+			"RADOS Pool quota max bytes (0 = unlimited)",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(quota.QuotaMaxBytes))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rados", "pool_quota_max_objects"),
+			"RADOS Pool quota max objects (0 = unlimited)",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(quota.QuotaMaxObjects))
+
+		c.updateQuotaUsedRatio("bytes", quota.QuotaMaxBytes, stats.Num_bytes, labels, ch)
+		c.updateQuotaUsedRatio("objects", quota.QuotaMaxObjects, stats.Num_objects, labels, ch)
+	}
+
+	return errs
+}
+
+func (c *RADOSPools) poolStats(conn *rados.Conn, pool string) (rados.PoolStat, error) {
+	ioctx, err := conn.OpenIOContext(pool)
+	if err != nil {
+		return rados.PoolStat{}, err
+	}
+	defer ioctx.Destroy()
+
+	return ioctx.GetPoolStats()
+}
+
+// updateQuotaUsedRatio emits the used ratio of the quota, nothing is emitted when there is no quota.
+func (c *RADOSPools) updateQuotaUsedRatio(quotaType string, max uint64, used uint64, labels map[string]string, ch chan<- prometheus.Metric) {
+	if max == 0 {
+		return
+	}
+
+	quotaLabels := map[string]string{
+		"quota": quotaType,
+	}
+	for k, v := range labels {
+		quotaLabels[k] = v
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rados", "pool_quota_used_ratio"),
+		"RADOS Pool quota used ratio",
+		nil, quotaLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(used)/float64(max))
+}
+
+func radosGetPoolQuota(conn *rados.Conn, pool string) (*radosPoolQuota, error) {
+	cmd, err := json.Marshal(map[string]string{
+		"prefix": "osd pool get-quota",
+		"pool":   pool,
+		"format": "json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out, status, err := conn.MonCommand(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("%w (status: %s)", err, status)
+	}
+
+	quota := &radosPoolQuota{}
+	if err := json.Unmarshal(out, quota); err != nil {
+		return nil, err
+	}
+
+	return quota, nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+	"go.uber.org/multierr"
+)
+
+// rbdPools returns the pools selected by the `rbd.pools` config (all pools if empty).
+func rbdPools(client *Client) ([]string, error) {
+	pools, err := client.Rados.ListPools()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(client.Config.RBD.Pools) > 0 {
+		// Remove any pools not in our list
+		pools = slices.DeleteFunc(pools, func(pool string) bool {
+			return !slices.ContainsFunc(client.Config.RBD.Pools, func(rp *config.RBDPool) bool {
+				return rp.Name == pool
+			})
+		})
+	}
+
+	return pools, nil
+}
+
+// rbdPoolConfig returns the `rbd.pools` config entry of the pool, nil if there is none.
+func rbdPoolConfig(client *Client, pool string) *config.RBDPool {
+	if idx := slices.IndexFunc(client.Config.RBD.Pools, func(rp *config.RBDPool) bool {
+		return rp.Name == pool
+	}); idx > -1 {
+		return client.Config.RBD.Pools[idx]
+	}
+
+	return nil
+}
+
+// rbdNamespaces returns the namespaces of the pool to collect metrics from.
+func rbdNamespaces(client *Client, ioctx *rados.IOContext, pool string) ([]string, error) {
+	namespaces := []string{
+		rados.AllNamespaces,
+	}
+
+	pNamespaces, err := rbd.NamespaceList(ioctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(pNamespaces) > 0 {
+		namespaces = pNamespaces
+	}
+
+	if poolCfg := rbdPoolConfig(client, pool); poolCfg != nil && len(poolCfg.Namespaces) > 0 {
+		if len(pNamespaces) > 0 {
+			// Only keep namespaces that exist in the pool
+			namespaces = slices.DeleteFunc(slices.Clone(poolCfg.Namespaces), func(namespace string) bool {
+				return !slices.Contains(pNamespaces, namespace)
+			})
+		} else {
+			namespaces = poolCfg.Namespaces
+		}
+	}
+
+	return namespaces, nil
+}
+
+// rbdLabels returns the pool and (if set) namespace labels.
+func rbdLabels(pool string, namespace string) map[string]string {
+	labels := map[string]string{
+		"pool": pool,
+	}
+	if namespace != rados.AllNamespaces {
+		labels["namespace"] = namespace
+	}
+
+	return labels
+}
+
+// forEachRBDNamespace calls fn for each pool and namespace selected by the `rbd.pools` config,
+// the IO context is set to the namespace. Errors are collected and don't stop the iteration.
+func forEachRBDNamespace(ctx context.Context, client *Client, fn func(ioctx *rados.IOContext, pool string, namespace string) error) error {
+	pools, err := rbdPools(client)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	// List pools and iterate over each
+	for _, pool := range pools {
+		if err := ctx.Err(); err != nil {
+			return multierr.Append(errs, err)
+		}
+
+		ioctx, err := client.Rados.OpenIOContext(pool)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to open rados IO context for %s pool. %w", pool, err))
+			continue
+		}
+
+		namespaces, err := rbdNamespaces(client, ioctx, pool)
+		if err != nil {
+			ioctx.Destroy()
+			errs = multierr.Append(errs, fmt.Errorf("failed to list namespaces for %s pool. %w", pool, err))
+			continue
+		}
+
+		for _, namespace := range namespaces {
+			ioctx.SetNamespace(namespace)
+
+			if err := fn(ioctx, pool, namespace); err != nil {
+				errs = multierr.Append(errs, err)
+			}
+		}
+
+		ioctx.Destroy()
+	}
+
+	return errs
+}
+
+// forEachRBDImage calls fn for each image in the pools and namespaces selected by the `rbd.pools`
+// config. The image is opened read-only and closed after fn returns.
+func forEachRBDImage(ctx context.Context, client *Client, fn func(ioctx *rados.IOContext, pool string, namespace string, image *rbd.Image) error) error {
+	return forEachRBDNamespace(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string) error {
+		images, err := rbd.GetImageNames(ioctx)
+		if err != nil {
+			return fmt.Errorf("failed to get image names from %s pool (namespace: %s). %w", pool, namespace, err)
+		}
+
+		var errs error
+		for _, name := range images {
+			if err := ctx.Err(); err != nil {
+				return multierr.Append(errs, err)
+			}
+
+			image, err := rbd.OpenImageReadOnly(ioctx, name, rbd.NoSnapshot)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to open image %s/%s (namespace: %s). %w", pool, name, namespace, err))
+				continue
+			}
+
+			if err := fn(ioctx, pool, namespace, image); err != nil {
+				errs = multierr.Append(errs, err)
+			}
+
+			if err := image.Close(); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to close image %s/%s (namespace: %s). %w", pool, name, namespace, err))
+			}
+		}
+
+		return errs
+	})
+}
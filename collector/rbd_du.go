@@ -0,0 +1,228 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+var errRBDDiskUsageTimeout = errors.New("rbd disk usage calculation timed out")
+
+// RBDDiskUsage calculates the used bytes of images and their snapshots like `rbd du`.
+// Images are processed concurrently, so the metric descriptions aren't stored in the struct.
+type RBDDiskUsage struct{}
+
+func init() {
+	Factories["rbd_du"] = NewRBDDiskUsage
+}
+
+func NewRBDDiskUsage() (Collector, error) {
+	return &RBDDiskUsage{}, nil
+}
+
+func (c *RBDDiskUsage) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	duCfg := client.Config.RBD.DU
+	concurrency := max(duCfg.Concurrency, 1)
+
+	return forEachRBDNamespace(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string) error {
+		images, err := rbd.GetImageNames(ioctx)
+		if err != nil {
+			return fmt.Errorf("failed to get image names from %s pool (namespace: %s). %w", pool, namespace, err)
+		}
+
+		var errs error
+		errsMu := sync.Mutex{}
+		sem := make(chan struct{}, concurrency)
+		wg := sync.WaitGroup{}
+
+		for _, name := range images {
+			if err := ctx.Err(); err != nil {
+				errs = multierr.Append(errs, err)
+				break
+			}
+
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				if err := c.updateImage(ctx, duCfg, ioctx, pool, namespace, name, ch); err != nil {
+					errsMu.Lock()
+					errs = multierr.Append(errs, fmt.Errorf("failed to calculate disk usage for %s/%s (namespace: %s). %w", pool, name, namespace, err))
+					errsMu.Unlock()
+				}
+			}(name)
+		}
+
+		wg.Wait()
+
+		return errs
+	})
+}
+
+func (c *RBDDiskUsage) updateImage(ctx context.Context, duCfg config.RBDDiskUsage, ioctx *rados.IOContext, pool string, namespace string, name string, ch chan<- prometheus.Metric) error {
+	image, err := rbd.OpenImageReadOnly(ioctx, name, rbd.NoSnapshot)
+	if err != nil {
+		return err
+	}
+	defer image.Close()
+
+	features, err := image.GetFeatures()
+	if err != nil {
+		return err
+	}
+
+	// Without fast-diff every object of the image needs to be checked
+	if features&rbd.FeatureFastDiff == 0 && duCfg.NoFastDiffMode == config.RBDDiskUsageModeSkip {
+		return nil
+	}
+
+	id, err := image.GetId()
+	if err != nil {
+		return err
+	}
+
+	size, err := image.GetSize()
+	if err != nil {
+		return err
+	}
+
+	snaps, err := image.GetSnapshotNames()
+	if err != nil {
+		return err
+	}
+	// Snapshot ids are increasing, so this orders the snapshots from oldest to newest
+	slices.SortFunc(snaps, func(a, b rbd.SnapInfo) int {
+		if a.Id < b.Id {
+			return -1
+		} else if a.Id > b.Id {
+			return 1
+		}
+		return 0
+	})
+
+	deadline := time.Now().Add(duCfg.ImageTimeout)
+
+	labels := rbdLabels(pool, namespace)
+	labels["id"] = id
+	labels["name"] = name
+
+	// Each snapshot's usage is the data changed since the previous snapshot
+	fromSnap := rbd.NoSnapshot
+	for _, snap := range snaps {
+		if rbdDiskUsageExpired(ctx, deadline) {
+			return errRBDDiskUsageTimeout
+		}
+
+		snapImage, err := rbd.OpenImageReadOnly(ioctx, name, snap.Name)
+		if err != nil {
+			return fmt.Errorf("failed to open snapshot %s. %w", snap.Name, err)
+		}
+
+		used, err := rbdDiffUsed(ctx, snapImage, fromSnap, snap.Size, deadline)
+		snapImage.Close()
+		if err != nil {
+			return fmt.Errorf("failed to diff snapshot %s. %w", snap.Name, err)
+		}
+
+		snapLabels := map[string]string{
+			"snapshot": snap.Name,
+		}
+		for k, v := range labels {
+			snapLabels[k] = v
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "snapshot_used_bytes"),
+				"RBD Snapshot used bytes (data changed since the previous snapshot)",
+				nil, snapLabels),
+			prometheus.GaugeValue, float64(used))
+
+		fromSnap = snap.Name
+	}
+
+	used, err := rbdDiffUsed(ctx, image, fromSnap, size, deadline)
+	if err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "image_used_bytes"),
+			"RBD Image used bytes (without snapshots)",
+			nil, labels),
+		prometheus.GaugeValue, float64(used))
+
+	return nil
+}
+
+// rbdDiskUsageExpired returns true when the deadline or context of the disk usage calculation is exceeded.
+func rbdDiskUsageExpired(ctx context.Context, deadline time.Time) bool {
+	return time.Now().After(deadline) || ctx.Err() != nil
+}
+
+// rbdDiffUsed sums up the allocated extents of the image since the given snapshot.
+// The diff is aborted when the deadline or context is exceeded, which is only checked
+// for each extent, so a single slow extent can exceed the deadline.
+func rbdDiffUsed(ctx context.Context, image *rbd.Image, fromSnap string, size uint64, deadline time.Time) (uint64, error) {
+	if rbdDiskUsageExpired(ctx, deadline) {
+		return 0, errRBDDiskUsageTimeout
+	}
+
+	var used uint64
+	timedOut := false
+
+	err := image.DiffIterate(rbd.DiffIterateConfig{
+		SnapName:      fromSnap,
+		Offset:        0,
+		Length:        size,
+		IncludeParent: rbd.ExcludeParent,
+		WholeObject:   rbd.EnableWholeObject,
+		Callback: func(offset uint64, length uint64, exists int, _ interface{}) int {
+			if rbdDiskUsageExpired(ctx, deadline) {
+				timedOut = true
+				return -1
+			}
+
+			if exists != 0 {
+				used += length
+			}
+			return 0
+		},
+	})
+	if timedOut {
+		return 0, errRBDDiskUsageTimeout
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return used, nil
+}
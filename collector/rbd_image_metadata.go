@@ -0,0 +1,127 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+)
+
+// rbdImageMetadataPresets are the image metadata keys (and their label) set by common provisioners.
+var rbdImageMetadataPresets = map[string][]*config.RBDImageMetadataKey{
+	config.RBDImageMetadataPresetCephCSI: {
+		{Key: "csi.storage.k8s.io/pvc/name", Label: "pvc_name"},
+		{Key: "csi.storage.k8s.io/pvc/namespace", Label: "pvc_namespace"},
+		{Key: "csi.storage.k8s.io/pv/name", Label: "pv_name"},
+	},
+}
+
+// rbdImageMetadataReservedLabels are the labels already set on the metadata info metric.
+var rbdImageMetadataReservedLabels = []string{"pool", "namespace", "id", "name"}
+
+type RBDImageMetadata struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rbd_image_metadata"] = NewRBDImageMetadata
+}
+
+func NewRBDImageMetadata() (Collector, error) {
+	return &RBDImageMetadata{}, nil
+}
+
+func (c *RBDImageMetadata) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	keys, err := rbdImageMetadataKeys(client.Config.RBD.Metadata)
+	if err != nil {
+		return err
+	}
+
+	return forEachRBDImage(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string, image *rbd.Image) error {
+		name := image.GetName()
+
+		id, err := image.GetId()
+		if err != nil {
+			return fmt.Errorf("failed to get image id for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		metadata, err := image.ListMetadata()
+		if err != nil {
+			return fmt.Errorf("failed to list image metadata for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		labels := rbdLabels(pool, namespace)
+		labels["id"] = id
+		labels["name"] = name
+		// Missing keys result in an empty label so that every image has an info metric
+		for _, key := range keys {
+			labels[key.Label] = metadata[key.Key]
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "image_metadata_info"),
+			"RBD Image metadata info (configured image metadata keys as labels)",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, 1)
+
+		return nil
+	})
+}
+
+// rbdImageMetadataKeys returns the keys of the configured presets and keys.
+func rbdImageMetadataKeys(cfg config.RBDImageMetadata) ([]*config.RBDImageMetadataKey, error) {
+	presets := []string{config.RBDImageMetadataPresetCephCSI}
+	if cfg.Presets != nil {
+		presets = *cfg.Presets
+	}
+
+	keys := []*config.RBDImageMetadataKey{}
+	for _, preset := range presets {
+		presetKeys, ok := rbdImageMetadataPresets[preset]
+		if !ok {
+			return nil, fmt.Errorf("unknown rbd image metadata preset %q", preset)
+		}
+		keys = append(keys, presetKeys...)
+	}
+	keys = append(keys, cfg.Keys...)
+
+	labels := map[string]bool{}
+	for _, label := range rbdImageMetadataReservedLabels {
+		labels[label] = true
+	}
+	for _, key := range keys {
+		if key.Key == "" || key.Label == "" {
+			return nil, fmt.Errorf("rbd image metadata key and label must be set (key: %q, label: %q)", key.Key, key.Label)
+		}
+		if !model.LabelName(key.Label).IsValid() {
+			return nil, fmt.Errorf("rbd image metadata label %q for key %q is not a valid label name", key.Label, key.Key)
+		}
+		if labels[key.Label] {
+			return nil, fmt.Errorf("rbd image metadata label %q for key %q is already used", key.Label, key.Key)
+		}
+		labels[key.Label] = true
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// rbdMaxCloneDepth limits how many parents are followed to calculate the clone depth.
+const rbdMaxCloneDepth = 64
+
+type RBDImages struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rbd_images"] = NewRBDImages
+}
+
+func NewRBDImages() (Collector, error) {
+	return &RBDImages{}, nil
+}
+
+func (c *RBDImages) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	return forEachRBDImage(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string, image *rbd.Image) error {
+		name := image.GetName()
+
+		id, err := image.GetId()
+		if err != nil {
+			return fmt.Errorf("failed to get image id for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		labels := rbdLabels(pool, namespace)
+		labels["id"] = id
+		labels["name"] = name
+
+		stat, err := image.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to get image stat for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		features, err := image.GetFeatures()
+		if err != nil {
+			return fmt.Errorf("failed to get image features for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+		// Feature names are returned in map order, sort them to keep the label value stable
+		featureSet := rbd.FeatureSet(features)
+		featureNames := slices.Clone(featureSet.Names())
+		slices.Sort(featureNames)
+
+		parent, err := image.GetParent()
+		if err != nil && !errors.Is(err, rbd.ErrNotExist) {
+			return fmt.Errorf("failed to get image parent for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		infoLabels := map[string]string{
+			"features":         strings.Join(featureNames, ","),
+			"parent_pool":      "",
+			"parent_namespace": "",
+			"parent_image":     "",
+			"parent_snapshot":  "",
+		}
+		if parent != nil {
+			infoLabels["parent_pool"] = parent.Image.PoolName
+			infoLabels["parent_namespace"] = parent.Image.PoolNamespace
+			infoLabels["parent_image"] = parent.Image.ImageName
+			infoLabels["parent_snapshot"] = parent.Snap.SnapName
+		}
+		for k, v := range labels {
+			infoLabels[k] = v
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "image_info"),
+			"RBD Image info (features and parent image)",
+			nil, infoLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, 1)
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "image_object_size_bytes"),
+			"RBD Image object size",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(stat.Obj_size))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "image_order"),
+			"RBD Image order (object size as power of two)",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(stat.Order))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "image_objects"),
+			"RBD Image number of (provisioned) objects",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(stat.Num_objs))
+
+		var errs error
+
+		stripeUnit, err := image.GetStripeUnit()
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get image stripe unit for %s/%s (namespace: %s). %w", pool, name, namespace, err))
+		} else {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "image_stripe_unit_bytes"),
+				"RBD Image stripe unit",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(stripeUnit))
+		}
+
+		stripeCount, err := image.GetStripeCount()
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get image stripe count for %s/%s (namespace: %s). %w", pool, name, namespace, err))
+		} else {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "image_stripe_count"),
+				"RBD Image stripe count",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(stripeCount))
+		}
+
+		snaps, err := image.GetSnapshotNames()
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get image snapshots for %s/%s (namespace: %s). %w", pool, name, namespace, err))
+		} else {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "image_snapshots"),
+				"RBD Image number of snapshots",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(len(snaps)))
+		}
+
+		depth, err := c.cloneDepth(client, parent)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get clone depth for %s/%s (namespace: %s). %w", pool, name, namespace, err))
+		} else {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "image_clone_depth"),
+				"RBD Image number of parents in the clone chain (0 = not a clone)",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(depth))
+		}
+
+		for metric, getTimestamp := range map[string]func() (rbd.Timespec, error){
+			"image_created_timestamp_seconds":  image.GetCreateTimestamp,
+			"image_modified_timestamp_seconds": image.GetModifyTimestamp,
+			"image_accessed_timestamp_seconds": image.GetAccessTimestamp,
+		} {
+			ts, err := getTimestamp()
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to get image %s for %s/%s (namespace: %s). %w", metric, pool, name, namespace, err))
+				continue
+			}
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", metric),
+				"RBD Image "+strings.TrimPrefix(strings.TrimSuffix(metric, "_timestamp_seconds"), "image_")+" timestamp",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(ts.Sec)+float64(ts.Nsec)/1e9)
+		}
+
+		return errs
+	})
+}
+
+// cloneDepth follows the parents of an image to calculate the length of the clone chain.
+func (c *RBDImages) cloneDepth(client *Client, parent *rbd.ParentInfo) (int, error) {
+	depth := 0
+	for parent != nil && depth < rbdMaxCloneDepth {
+		depth++
+
+		ioctx, err := client.Rados.OpenIOContext(parent.Image.PoolName)
+		if err != nil {
+			return depth, err
+		}
+		ioctx.SetNamespace(parent.Image.PoolNamespace)
+
+		// Parents that have been moved to the trash can only be opened by id
+		var image *rbd.Image
+		if parent.Image.Trash {
+			image, err = rbd.OpenImageByIdReadOnly(ioctx, parent.Image.ImageID, rbd.NoSnapshot)
+		} else {
+			image, err = rbd.OpenImageReadOnly(ioctx, parent.Image.ImageName, rbd.NoSnapshot)
+		}
+		if err != nil {
+			ioctx.Destroy()
+			return depth, err
+		}
+
+		parent, err = image.GetParent()
+		image.Close()
+		ioctx.Destroy()
+		if err != nil {
+			if errors.Is(err, rbd.ErrNotExist) {
+				return depth, nil
+			}
+			return depth, err
+		}
+	}
+
+	return depth, nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+type RBDMigrationGroups struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rbd_migration_groups"] = NewRBDMigrationGroups
+}
+
+func NewRBDMigrationGroups() (Collector, error) {
+	return &RBDMigrationGroups{}, nil
+}
+
+func (c *RBDMigrationGroups) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	// Images being migrated are counted per pool and namespace (of the destination image)
+	migrating := map[string]map[string]float64{}
+
+	errs := forEachRBDNamespace(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string) error {
+		if _, ok := migrating[pool]; !ok {
+			migrating[pool] = map[string]float64{}
+		}
+		migrating[pool][namespace] = 0
+
+		return c.updateGroups(ioctx, pool, namespace, ch)
+	})
+
+	errs = multierr.Append(errs, forEachRBDImage(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string, image *rbd.Image) error {
+		name := image.GetName()
+
+		features, err := image.GetFeatures()
+		if err != nil {
+			return fmt.Errorf("failed to get image features for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+		if features&rbd.FeatureMigrating == 0 {
+			return nil
+		}
+		if _, ok := migrating[pool]; !ok {
+			migrating[pool] = map[string]float64{}
+		}
+		migrating[pool][namespace]++
+
+		return c.updateMigration(client, ioctx, pool, namespace, image, ch)
+	}))
+
+	for pool, namespaces := range migrating {
+		for namespace, count := range namespaces {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "migrating_images"),
+				"RBD number of images under migration",
+				nil, rbdLabels(pool, namespace))
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, count)
+		}
+	}
+
+	return errs
+}
+
+func (c *RBDMigrationGroups) updateMigration(client *Client, ioctx *rados.IOContext, pool string, namespace string, image *rbd.Image, ch chan<- prometheus.Metric) error {
+	name := image.GetName()
+
+	id, err := image.GetId()
+	if err != nil {
+		return fmt.Errorf("failed to get image id for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+	}
+
+	status, err := rbd.MigrationStatus(ioctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get migration status for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+	}
+
+	var errs error
+
+	// The source pool id is negative when the image is imported from an external source
+	sourcePool := ""
+	if status.SourcePoolID >= 0 {
+		sourcePool, err = client.Rados.GetPoolByID(int64(status.SourcePoolID))
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get migration source pool %d name for %s/%s (namespace: %s). %w", status.SourcePoolID, pool, name, namespace, err))
+		}
+	}
+	destPool, err := client.Rados.GetPoolByID(int64(status.DestPoolID))
+	if err != nil {
+		errs = multierr.Append(errs, fmt.Errorf("failed to get migration destination pool %d name for %s/%s (namespace: %s). %w", status.DestPoolID, pool, name, namespace, err))
+	}
+
+	labels := map[string]string{
+		"id":                id,
+		"name":              name,
+		"state":             rbdMigrationStateName(status.State),
+		"state_description": status.StateDescription,
+		"source_pool":       sourcePool,
+		"source_namespace":  status.SourcePoolNamespace,
+		"source_image":      status.SourceImageName,
+		"dest_pool":         destPool,
+		"dest_namespace":    status.DestPoolNamespace,
+		"dest_image":        status.DestImageName,
+	}
+	for k, v := range rbdLabels(pool, namespace) {
+		labels[k] = v
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rbd", "image_migration_info"),
+		"RBD Image migration state, source and destination",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	return errs
+}
+
+func (c *RBDMigrationGroups) updateGroups(ioctx *rados.IOContext, pool string, namespace string, ch chan<- prometheus.Metric) error {
+	groups, err := rbd.GroupList(ioctx)
+	if err != nil {
+		return fmt.Errorf("failed to list groups from %s pool (namespace: %s). %w", pool, namespace, err)
+	}
+
+	labels := rbdLabels(pool, namespace)
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rbd", "groups"),
+		"RBD number of groups",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(len(groups)))
+
+	var errs error
+	for _, group := range groups {
+		groupLabels := map[string]string{
+			"group": group,
+		}
+		for k, v := range labels {
+			groupLabels[k] = v
+		}
+
+		images, err := rbd.GroupImageList(ioctx, group)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to list images of group %s/%s (namespace: %s). %w", pool, group, namespace, err))
+		} else {
+			imageStates := map[string]float64{
+				"attached":   0,
+				"incomplete": 0,
+			}
+			for _, image := range images {
+				imageStates[rbdGroupImageStateName(image.State)]++
+			}
+
+			for state, count := range imageStates {
+				stateLabels := map[string]string{
+					"state": state,
+				}
+				for k, v := range groupLabels {
+					stateLabels[k] = v
+				}
+
+				c.current = prometheus.NewDesc(
+					prometheus.BuildFQName(MetricsNamespace, "rbd", "group_images"),
+					"RBD Group number of member images per state",
+					nil, stateLabels)
+				ch <- prometheus.MustNewConstMetric(
+					c.current, prometheus.GaugeValue, count)
+			}
+		}
+
+		snaps, err := rbd.GroupSnapList(ioctx, group)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to list snapshots of group %s/%s (namespace: %s). %w", pool, group, namespace, err))
+			continue
+		}
+
+		snapStates := map[string]float64{
+			"complete":   0,
+			"incomplete": 0,
+		}
+		for _, snap := range snaps {
+			snapStates[rbdGroupSnapStateName(snap.State)]++
+		}
+
+		for state, count := range snapStates {
+			stateLabels := map[string]string{
+				"state": state,
+			}
+			for k, v := range groupLabels {
+				stateLabels[k] = v
+			}
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "group_snapshots"),
+				"RBD Group number of group snapshots per state",
+				nil, stateLabels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, count)
+		}
+	}
+
+	return errs
+}
+
+func rbdMigrationStateName(state rbd.MigrationImageState) string {
+	switch state {
+	case rbd.MigrationImageError:
+		return "error"
+	case rbd.MigrationImagePreparing:
+		return "preparing"
+	case rbd.MigrationImagePrepared:
+		return "prepared"
+	case rbd.MigrationImageExecuting:
+		return "executing"
+	case rbd.MigrationImageExecuted:
+		return "executed"
+	case rbd.MigrationImageAborting:
+		return "aborting"
+	}
+
+	return "unknown"
+}
+
+func rbdGroupImageStateName(state rbd.GroupImageState) string {
+	switch state {
+	case rbd.GroupImageStateAttached:
+		return "attached"
+	case rbd.GroupImageStateIncomplete:
+		return "incomplete"
+	}
+
+	return "unknown"
+}
+
+func rbdGroupSnapStateName(state rbd.GroupSnapState) string {
+	switch state {
+	case rbd.GroupSnapStateComplete:
+		return "complete"
+	case rbd.GroupSnapStateIncomplete:
+		return "incomplete"
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// rbdMirrorLocalSite is used as the site label of the local site, when its site name can't be retrieved.
+const rbdMirrorLocalSite = "local"
+
+type RBDMirror struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rbd_mirror"] = NewRBDMirror
+}
+
+func NewRBDMirror() (Collector, error) {
+	return &RBDMirror{}, nil
+}
+
+func (c *RBDMirror) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	var errs error
+
+	localSite, err := rbd.GetMirrorSiteName(client.Rados)
+	if err != nil {
+		errs = multierr.Append(errs, fmt.Errorf("failed to get rbd mirror site name. %w", err))
+	}
+	if localSite == "" {
+		localSite = rbdMirrorLocalSite
+	}
+
+	// Peer sites are configured per pool, so they are only emitted once for each pool
+	peersDone := map[string]bool{}
+
+	errs = multierr.Append(errs, forEachRBDNamespace(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string) error {
+		labels := rbdLabels(pool, namespace)
+
+		mode, err := rbd.GetMirrorMode(ioctx)
+		if err != nil {
+			return fmt.Errorf("failed to get mirror mode of %s pool (namespace: %s). %w", pool, namespace, err)
+		}
+
+		modeLabels := map[string]string{
+			"mode": mode.String(),
+		}
+		for k, v := range labels {
+			modeLabels[k] = v
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "mirror_mode_info"),
+			"RBD Mirror mode of the pool (namespace)",
+			nil, modeLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, 1)
+
+		if mode == rbd.MirrorModeDisabled {
+			return nil
+		}
+
+		peers, err := rbd.ListMirrorPeerSite(ioctx)
+		if err != nil {
+			return fmt.Errorf("failed to list mirror peer sites of %s pool (namespace: %s). %w", pool, namespace, err)
+		}
+
+		// Used to resolve the mirror UUIDs of the image site statuses to their site name
+		siteNames := map[string]string{
+			"": localSite,
+		}
+		for _, peer := range peers {
+			siteNames[peer.MirrorUUID] = peer.SiteName
+
+			if peersDone[pool] {
+				continue
+			}
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "mirror_peer_info"),
+				"RBD Mirror peer site of the pool",
+				nil, map[string]string{
+					"pool":        pool,
+					"uuid":        peer.UUID,
+					"site_name":   peer.SiteName,
+					"mirror_uuid": peer.MirrorUUID,
+					"client_name": peer.ClientName,
+					"direction":   rbdMirrorPeerDirectionName(peer.Direction),
+				})
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, 1)
+		}
+		peersDone[pool] = true
+
+		var errs error
+
+		summary, err := rbd.MirrorImageStatusSummary(ioctx)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get mirror image status summary of %s pool (namespace: %s). %w", pool, namespace, err))
+		} else {
+			for state, count := range summary {
+				stateLabels := map[string]string{
+					"state": state.String(),
+				}
+				for k, v := range labels {
+					stateLabels[k] = v
+				}
+
+				c.current = prometheus.NewDesc(
+					prometheus.BuildFQName(MetricsNamespace, "rbd", "mirror_images"),
+					"RBD Mirror number of images per local status state",
+					nil, stateLabels)
+				ch <- prometheus.MustNewConstMetric(
+					c.current, prometheus.GaugeValue, float64(count))
+			}
+		}
+
+		infos, err := rbd.MirrorImageInfoList(ioctx, nil, "", 0)
+		if err != nil {
+			return multierr.Append(errs, fmt.Errorf("failed to list mirror image info of %s pool (namespace: %s). %w", pool, namespace, err))
+		}
+		imageInfos := make(map[string]rbd.MirrorImageInfoItem, len(infos))
+		for _, info := range infos {
+			imageInfos[info.ID] = info
+		}
+
+		statuses, err := rbd.MirrorImageGlobalStatusList(ioctx, "", 0)
+		if err != nil {
+			return multierr.Append(errs, fmt.Errorf("failed to list mirror image status of %s pool (namespace: %s). %w", pool, namespace, err))
+		}
+
+		for _, status := range statuses {
+			c.updateImage(labels, siteNames, imageInfos[status.ID], status, ch)
+		}
+
+		return errs
+	}))
+
+	return errs
+}
+
+func (c *RBDMirror) updateImage(poolLabels map[string]string, siteNames map[string]string, info rbd.MirrorImageInfoItem, status rbd.GlobalMirrorImageIDAndStatus, ch chan<- prometheus.Metric) {
+	labels := map[string]string{
+		"id":   status.ID,
+		"name": status.Status.Name,
+	}
+	for k, v := range poolLabels {
+		labels[k] = v
+	}
+
+	// The image mirror mode is only known when the image is in the mirror image info list
+	mode := ""
+	if info.ID != "" {
+		mode = info.Mode.String()
+	}
+
+	infoLabels := map[string]string{
+		"mode":      mode,
+		"state":     status.Status.Info.State.String(),
+		"global_id": status.Status.Info.GlobalID,
+	}
+	for k, v := range labels {
+		infoLabels[k] = v
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rbd", "mirror_image_info"),
+		"RBD Mirror image info (mirror mode and state)",
+		nil, infoLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	primary := 0.0
+	if status.Status.Info.Primary {
+		primary = 1
+	}
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rbd", "mirror_image_primary"),
+		"RBD Mirror image is primary",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, primary)
+
+	for _, siteStatus := range status.Status.SiteStatuses {
+		site, ok := siteNames[siteStatus.MirrorUUID]
+		if !ok || site == "" {
+			site = siteStatus.MirrorUUID
+		}
+
+		siteLabels := map[string]string{
+			"site": site,
+		}
+		for k, v := range labels {
+			siteLabels[k] = v
+		}
+
+		statusLabels := map[string]string{
+			"state": siteStatus.State.String(),
+			"up":    strconv.FormatBool(siteStatus.Up),
+		}
+		for k, v := range siteLabels {
+			statusLabels[k] = v
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "mirror_image_site_status"),
+			"RBD Mirror image status per site (e.g., `up` and `replaying` for up+replaying)",
+			nil, statusLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, 1)
+
+		up := 0.0
+		if siteStatus.Up {
+			up = 1
+		}
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "mirror_image_site_up"),
+			"RBD Mirror image rbd-mirror daemon is up per site",
+			nil, siteLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, up)
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "mirror_image_site_last_update_timestamp_seconds"),
+			"RBD Mirror image last status update timestamp per site",
+			nil, siteLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(siteStatus.LastUpdate))
+	}
+}
+
+func rbdMirrorPeerDirectionName(direction rbd.MirrorPeerDirection) string {
+	switch direction {
+	case rbd.MirrorPeerDirectionRx:
+		return "rx-only"
+	case rbd.MirrorPeerDirectionTx:
+		return "tx-only"
+	case rbd.MirrorPeerDirectionRxTx:
+		return "rx-tx"
+	}
+
+	return "unknown"
+}
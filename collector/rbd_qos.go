@@ -0,0 +1,212 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+const (
+	// rbdConfigMetadataPrefix is the metadata key prefix of config overrides (e.g., `rbd config image set`)
+	rbdConfigMetadataPrefix = "conf_"
+	// rbdQoSOptionPrefix is the prefix of the QoS config options, it is removed for the `option` label
+	rbdQoSOptionPrefix = "rbd_qos_"
+)
+
+// rbdQoSOptions are the QoS config options (without the `rbd_qos_` prefix) that can be overridden.
+var rbdQoSOptions = []string{
+	"iops_limit",
+	"iops_burst",
+	"iops_burst_seconds",
+	"bps_limit",
+	"bps_burst",
+	"bps_burst_seconds",
+	"read_iops_limit",
+	"read_iops_burst",
+	"read_iops_burst_seconds",
+	"write_iops_limit",
+	"write_iops_burst",
+	"write_iops_burst_seconds",
+	"read_bps_limit",
+	"read_bps_burst",
+	"read_bps_burst_seconds",
+	"write_bps_limit",
+	"write_bps_burst",
+	"write_bps_burst_seconds",
+}
+
+// rbdQoSOverride is a QoS config override and the scope (pool, namespace or image) it is set on.
+type rbdQoSOverride struct {
+	Scope string
+	Value float64
+}
+
+type RBDQoS struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rbd_qos"] = NewRBDQoS
+}
+
+func NewRBDQoS() (Collector, error) {
+	return &RBDQoS{}, nil
+}
+
+func (c *RBDQoS) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	// Pool and namespace overrides are kept to calculate the effective image overrides
+	poolOverrides := map[string]map[string]rbdQoSOverride{}
+	nsOverrides := map[string]map[string]map[string]rbdQoSOverride{}
+
+	errs := forEachRBDNamespace(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string) error {
+		var errs error
+
+		if _, ok := poolOverrides[pool]; !ok {
+			// Pool overrides are stored in the default namespace
+			ioctx.SetNamespace("")
+			overrides, err := rbdQoSPoolOverrides(ioctx, "pool")
+			ioctx.SetNamespace(namespace)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to get qos config of %s pool. %w", pool, err))
+			}
+			poolOverrides[pool] = overrides
+			nsOverrides[pool] = map[string]map[string]rbdQoSOverride{}
+
+			c.updateOverrides("qos_config", "RBD QoS config override per pool or namespace", map[string]string{
+				"pool": pool,
+			}, overrides, ch)
+		}
+
+		if namespace == rados.AllNamespaces || namespace == "" {
+			return errs
+		}
+
+		overrides, err := rbdQoSPoolOverrides(ioctx, "namespace")
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get qos config of %s pool (namespace: %s). %w", pool, namespace, err))
+		}
+		nsOverrides[pool][namespace] = overrides
+
+		c.updateOverrides("qos_config", "RBD QoS config override per pool or namespace", rbdLabels(pool, namespace), overrides, ch)
+
+		return errs
+	})
+
+	errs = multierr.Append(errs, forEachRBDImage(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string, image *rbd.Image) error {
+		name := image.GetName()
+
+		id, err := image.GetId()
+		if err != nil {
+			return fmt.Errorf("failed to get image id for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		metadata, err := image.ListMetadata()
+		if err != nil {
+			return fmt.Errorf("failed to list image metadata for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		labels := rbdLabels(pool, namespace)
+		labels["id"] = id
+		labels["name"] = name
+
+		var errs error
+		overrides := map[string]rbdQoSOverride{}
+		for _, option := range rbdQoSOptions {
+			value, ok := metadata[rbdConfigMetadataPrefix+rbdQoSOptionPrefix+option]
+			if !ok {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to parse qos config %s of %s/%s (namespace: %s). %w", option, pool, name, namespace, err))
+				continue
+			}
+			overrides[option] = rbdQoSOverride{Scope: "image", Value: parsed}
+		}
+
+		c.updateOverrides("image_qos_config", "RBD Image QoS config override", labels, overrides, ch)
+
+		// Image overrides take precedence over namespace overrides, which take precedence over pool overrides
+		effective := map[string]rbdQoSOverride{}
+		for option, override := range poolOverrides[pool] {
+			effective[option] = override
+		}
+		for option, override := range nsOverrides[pool][namespace] {
+			effective[option] = override
+		}
+		for option, override := range overrides {
+			effective[option] = override
+		}
+
+		c.updateOverrides("image_qos_effective_config", "RBD Image effective QoS config override (scope label shows where it is set)", labels, effective, ch)
+
+		return errs
+	}))
+
+	return errs
+}
+
+func (c *RBDQoS) updateOverrides(metric string, help string, labels map[string]string, overrides map[string]rbdQoSOverride, ch chan<- prometheus.Metric) {
+	for option, override := range overrides {
+		optionLabels := map[string]string{
+			"option": option,
+			"scope":  override.Scope,
+		}
+		for k, v := range labels {
+			optionLabels[k] = v
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", metric),
+			help,
+			nil, optionLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, override.Value)
+	}
+}
+
+// rbdQoSPoolOverrides returns the QoS config overrides set in the pool metadata of the IO context's namespace.
+func rbdQoSPoolOverrides(ioctx *rados.IOContext, scope string) (map[string]rbdQoSOverride, error) {
+	var errs error
+	overrides := map[string]rbdQoSOverride{}
+	for _, option := range rbdQoSOptions {
+		value, err := rbd.GetPoolMetadata(ioctx, rbdConfigMetadataPrefix+rbdQoSOptionPrefix+option)
+		if err != nil {
+			if !errors.Is(err, rbd.ErrNotExist) {
+				errs = multierr.Append(errs, fmt.Errorf("failed to get qos config %s. %w", option, err))
+			}
+			continue
+		}
+
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to parse qos config %s. %w", option, err))
+			continue
+		}
+		overrides[option] = rbdQoSOverride{Scope: scope, Value: parsed}
+	}
+
+	return overrides, errs
+}
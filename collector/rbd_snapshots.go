@@ -0,0 +1,159 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// rbdSnapNamespaceTypeMirror is `RBD_SNAP_NAMESPACE_TYPE_MIRROR`, which go-ceph doesn't provide a constant for.
+const rbdSnapNamespaceTypeMirror = rbd.SnapNamespaceType(3)
+
+type RBDSnapshots struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rbd_snapshots"] = NewRBDSnapshots
+}
+
+func NewRBDSnapshots() (Collector, error) {
+	return &RBDSnapshots{}, nil
+}
+
+func (c *RBDSnapshots) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	return forEachRBDImage(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string, image *rbd.Image) error {
+		name := image.GetName()
+
+		id, err := image.GetId()
+		if err != nil {
+			return fmt.Errorf("failed to get image id for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		snaps, err := image.GetSnapshotNames()
+		if err != nil {
+			return fmt.Errorf("failed to get image snapshots for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		var errs error
+		for _, snap := range snaps {
+			labels := rbdLabels(pool, namespace)
+			labels["id"] = id
+			labels["name"] = name
+			labels["snapshot"] = snap.Name
+
+			nsType, err := image.GetSnapNamespaceType(snap.Id)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to get snapshot %s namespace type for %s/%s (namespace: %s). %w", snap.Name, pool, name, namespace, err))
+				continue
+			}
+
+			infoLabels := map[string]string{
+				"snapshot_id":    strconv.FormatUint(snap.Id, 10),
+				"namespace_type": rbdSnapNamespaceTypeName(nsType),
+			}
+			for k, v := range labels {
+				infoLabels[k] = v
+			}
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "snapshot_info"),
+				"RBD Snapshot info",
+				nil, infoLabels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, 1)
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "snapshot_size_bytes"),
+				"RBD Snapshot provisioned size",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(snap.Size))
+
+			ts, err := image.GetSnapTimestamp(snap.Id)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to get snapshot %s timestamp for %s/%s (namespace: %s). %w", snap.Name, pool, name, namespace, err))
+			} else {
+				c.current = prometheus.NewDesc(
+					prometheus.BuildFQName(MetricsNamespace, "rbd", "snapshot_created_timestamp_seconds"),
+					"RBD Snapshot creation timestamp",
+					nil, labels)
+				ch <- prometheus.MustNewConstMetric(
+					c.current, prometheus.GaugeValue, float64(ts.Sec)+float64(ts.Nsec)/1e9)
+			}
+
+			// Only user snapshots can be protected
+			protected := 0.0
+			if nsType == rbd.SnapNamespaceTypeUser {
+				isProtected, err := image.GetSnapshot(snap.Name).IsProtected()
+				if err != nil {
+					errs = multierr.Append(errs, fmt.Errorf("failed to get snapshot %s protected state for %s/%s (namespace: %s). %w", snap.Name, pool, name, namespace, err))
+				} else if isProtected {
+					protected = 1
+				}
+			}
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "snapshot_protected"),
+				"RBD Snapshot protected",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, protected)
+
+			// Children are listed for the snapshot the image is set to
+			if err := image.SetSnapByID(snap.Id); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to set snapshot %s for %s/%s (namespace: %s). %w", snap.Name, pool, name, namespace, err))
+				continue
+			}
+			children, err := image.ListChildrenAttributes()
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to list snapshot %s children for %s/%s (namespace: %s). %w", snap.Name, pool, name, namespace, err))
+				continue
+			}
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "snapshot_children"),
+				"RBD Snapshot number of clones (children)",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(len(children)))
+		}
+
+		return errs
+	})
+}
+
+func rbdSnapNamespaceTypeName(nsType rbd.SnapNamespaceType) string {
+	switch nsType {
+	case rbd.SnapNamespaceTypeUser:
+		return "user"
+	case rbd.SnapNamespaceTypeGroup:
+		return "group"
+	case rbd.SnapNamespaceTypeTrash:
+		return "trash"
+	case rbdSnapNamespaceTypeMirror:
+		return "mirror"
+	}
+
+	return "unknown"
+}
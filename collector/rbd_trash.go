@@ -0,0 +1,191 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+const (
+	// rbdTrashObject is the object in each pool (namespace) that holds the trash entries in its omap
+	rbdTrashObject = "rbd_trash"
+	// rbdTrashKeyPrefix is the omap key prefix of the trash entries, followed by the image id
+	rbdTrashKeyPrefix = "id_"
+	// rbdTrashSpecHeaderLen is the length of the encoding header (version, compat version and length)
+	rbdTrashSpecHeaderLen = 6
+)
+
+// rbdTrashSources are the `cls::rbd::TrashImageSource` names, go-ceph doesn't expose the source.
+var rbdTrashSources = map[byte]string{
+	0: "user",
+	1: "mirroring",
+	2: "migration",
+	3: "removing",
+	4: "user_parent",
+}
+
+type RBDTrash struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rbd_trash"] = NewRBDTrash
+}
+
+func NewRBDTrash() (Collector, error) {
+	return &RBDTrash{}, nil
+}
+
+func (c *RBDTrash) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	return forEachRBDNamespace(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string) error {
+		entries, err := rbd.GetTrashList(ioctx)
+		if err != nil {
+			return fmt.Errorf("failed to get trash list from %s pool (namespace: %s). %w", pool, namespace, err)
+		}
+
+		var errs error
+
+		sources, err := rbdTrashSourcesOf(ioctx)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get trash entry sources from %s pool (namespace: %s). %w", pool, namespace, err))
+		}
+
+		labels := rbdLabels(pool, namespace)
+
+		// Count and size per source, so that e.g. images deferred by mirroring can be told apart
+		counts := map[string]float64{}
+		sizes := map[string]float64{}
+		for _, source := range rbdTrashSources {
+			counts[source] = 0
+			sizes[source] = 0
+		}
+
+		for _, entry := range entries {
+			source, ok := sources[entry.Id]
+			if !ok {
+				source = "unknown"
+			}
+			counts[source]++
+
+			entryLabels := map[string]string{
+				"id":     entry.Id,
+				"name":   entry.Name,
+				"source": source,
+			}
+			for k, v := range labels {
+				entryLabels[k] = v
+			}
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "trash_image_deletion_timestamp_seconds"),
+				"RBD Trash image time at which the image was moved to the trash",
+				nil, entryLabels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(entry.DeletionTime.Unix()))
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "trash_image_deferment_end_timestamp_seconds"),
+				"RBD Trash image time after which the image may be permanently deleted",
+				nil, entryLabels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(entry.DefermentEndTime.Unix()))
+
+			size, err := rbdTrashImageSize(ioctx, entry.Id)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to get size of trash image %s/%s (id: %s, namespace: %s). %w", pool, entry.Name, entry.Id, namespace, err))
+				continue
+			}
+			sizes[source] += float64(size)
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "trash_image_size_bytes"),
+				"RBD Trash image provisioned size",
+				nil, entryLabels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(size))
+		}
+
+		for source, count := range counts {
+			sourceLabels := map[string]string{
+				"source": source,
+			}
+			for k, v := range labels {
+				sourceLabels[k] = v
+			}
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "trash_images"),
+				"RBD Trash number of images per deletion source",
+				nil, sourceLabels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, count)
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "trash_size_bytes"),
+				"RBD Trash provisioned size of the images per deletion source",
+				nil, sourceLabels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, sizes[source])
+		}
+
+		return errs
+	})
+}
+
+// rbdTrashSourcesOf returns the deletion source of each trash entry by image id. The source is
+// the first field of the encoded `cls::rbd::TrashImageSpec` stored in the trash object's omap.
+func rbdTrashSourcesOf(ioctx *rados.IOContext) (map[string]string, error) {
+	values, err := ioctx.GetAllOmapValues(rbdTrashObject, "", rbdTrashKeyPrefix, 256)
+	if err != nil {
+		// The trash object only exists once an image has been moved to the trash
+		if errors.Is(err, rados.ErrNotFound) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+
+	sources := make(map[string]string, len(values))
+	for key, value := range values {
+		source := "unknown"
+		if len(value) > rbdTrashSpecHeaderLen {
+			if name, ok := rbdTrashSources[value[rbdTrashSpecHeaderLen]]; ok {
+				source = name
+			}
+		}
+		sources[key[len(rbdTrashKeyPrefix):]] = source
+	}
+
+	return sources, nil
+}
+
+// rbdTrashImageSize returns the provisioned size of an image in the trash, which can only be opened by id.
+func rbdTrashImageSize(ioctx *rados.IOContext, id string) (uint64, error) {
+	image, err := rbd.OpenImageByIdReadOnly(ioctx, id, rbd.NoSnapshot)
+	if err != nil {
+		return 0, err
+	}
+	defer image.Close()
+
+	return image.GetSize()
+}
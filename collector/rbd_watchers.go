@@ -0,0 +1,164 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ceph/go-ceph/rados"
+	"github.com/ceph/go-ceph/rbd"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+type RBDWatchers struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rbd_watchers"] = NewRBDWatchers
+}
+
+func NewRBDWatchers() (Collector, error) {
+	return &RBDWatchers{}, nil
+}
+
+func (c *RBDWatchers) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	return forEachRBDImage(ctx, client, func(ioctx *rados.IOContext, pool string, namespace string, image *rbd.Image) error {
+		name := image.GetName()
+
+		id, err := image.GetId()
+		if err != nil {
+			return fmt.Errorf("failed to get image id for %s/%s (namespace: %s). %w", pool, name, namespace, err)
+		}
+
+		labels := rbdLabels(pool, namespace)
+		labels["id"] = id
+		labels["name"] = name
+
+		var errs error
+
+		watchers, err := image.ListWatchers()
+		watchersListed := err == nil
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to list image watchers for %s/%s (namespace: %s). %w", pool, name, namespace, err))
+		} else {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "image_watchers"),
+				"RBD Image number of watchers (e.g., clients that have the image mapped)",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(len(watchers)))
+
+			if client.Config.RBD.Watchers.ClientAddresses {
+				for _, watcher := range watchers {
+					watcherLabels := map[string]string{
+						"address": watcher.Addr,
+						"client":  "client." + strconv.FormatInt(watcher.Id, 10),
+						"cookie":  strconv.FormatUint(watcher.Cookie, 10),
+					}
+					for k, v := range labels {
+						watcherLabels[k] = v
+					}
+
+					c.current = prometheus.NewDesc(
+						prometheus.BuildFQName(MetricsNamespace, "rbd", "image_watcher_info"),
+						"RBD Image watcher info",
+						nil, watcherLabels)
+					ch <- prometheus.MustNewConstMetric(
+						c.current, prometheus.GaugeValue, 1)
+				}
+			}
+		}
+
+		features, err := image.GetFeatures()
+		if err != nil {
+			return multierr.Append(errs, fmt.Errorf("failed to get image features for %s/%s (namespace: %s). %w", pool, name, namespace, err))
+		}
+
+		// Lock owners can only be queried for images with the exclusive-lock feature
+		if features&rbd.FeatureExclusiveLock == 0 {
+			return errs
+		}
+
+		owners, err := image.LockGetOwners()
+		if err != nil {
+			return multierr.Append(errs, fmt.Errorf("failed to get image lock owners for %s/%s (namespace: %s). %w", pool, name, namespace, err))
+		}
+
+		locked := 0.0
+		if len(owners) > 0 {
+			locked = 1
+		}
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rbd", "image_locked"),
+			"RBD Image has a lock owner",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, locked)
+
+		for _, owner := range owners {
+			ownerLabels := map[string]string{
+				"owner": owner.Owner,
+				"mode":  rbdLockModeName(owner.Mode),
+			}
+			for k, v := range labels {
+				ownerLabels[k] = v
+			}
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rbd", "image_lock_owner_info"),
+				"RBD Image lock owner info",
+				nil, ownerLabels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, 1)
+
+			// A lock owner without a watch is most likely a dead client
+			if watchersListed {
+				watching := 0.0
+				for _, watcher := range watchers {
+					if watcher.Addr == owner.Owner {
+						watching = 1
+						break
+					}
+				}
+
+				c.current = prometheus.NewDesc(
+					prometheus.BuildFQName(MetricsNamespace, "rbd", "image_lock_owner_watching"),
+					"RBD Image lock owner is watching the image (0 = lock is likely held by a dead client)",
+					nil, ownerLabels)
+				ch <- prometheus.MustNewConstMetric(
+					c.current, prometheus.GaugeValue, watching)
+			}
+		}
+
+		return errs
+	})
+}
+
+func rbdLockModeName(mode rbd.LockMode) string {
+	switch mode {
+	case rbd.LockModeExclusive:
+		return "exclusive"
+	case rbd.LockModeShared:
+		return "shared"
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/ceph/go-ceph/rgw/admin"
+)
+
+// rgwAdminError is returned by rgwAdminGet when the RGW admin API responds with an error.
+type rgwAdminError struct {
+	StatusCode int
+	Code       string `json:"Code"`
+}
+
+func (e *rgwAdminError) Error() string {
+	return fmt.Sprintf("rgw admin api returned status %d (code: %s)", e.StatusCode, e.Code)
+}
+
+// Is allows comparing the error with the go-ceph admin errors (e.g., `admin.ErrAccessDenied`).
+func (e *rgwAdminError) Is(target error) bool {
+	return e.Code != "" && target.Error() == e.Code
+}
+
+// rgwAdminGet calls RGW admin API endpoints (or fields of them) that the go-ceph admin client
+// doesn't support, e.g., `/user` (utilized size of the user stats), `/config`, `/realm/period`
+// and `/log` (multisite sync status), using the same credentials and HTTP client as the given API.
+func rgwAdminGet(ctx context.Context, api *admin.API, path string, args url.Values, out any) error {
+	if args == nil {
+		args = url.Values{}
+	}
+	args.Set("format", "json")
+
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api.Endpoint+"/admin"+path+sep+args.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	creds, err := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(api.AccessKey, api.SecretKey, "")).Retrieve(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, "UNSIGNED-PAYLOAD", "s3", "default", time.Now()); err != nil {
+		return err
+	}
+
+	resp, err := api.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		apiErr := &rgwAdminError{
+			StatusCode: resp.StatusCode,
+		}
+		// Error body is optional, the status code is enough to return an error
+		_ = json.Unmarshal(body, apiErr)
+		return apiErr
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal rgw admin api response for %s. %w", path, err)
+	}
+
+	return nil
+}
+
+// rgwZone is the (local) zone returned by the RGW admin API config endpoint.
+type rgwZone struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	RealmID     string   `json:"realm_id"`
+	TierType    string   `json:"tier_type"`
+	Endpoints   []string `json:"endpoints"`
+	SyncFromAll *bool    `json:"sync_from_all"`
+	SyncFrom    []string `json:"sync_from"`
+}
+
+// rgwZoneGroup is a zonegroup entry of a period's map.
+type rgwZoneGroup struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	APIName          string    `json:"api_name"`
+	IsMaster         bool      `json:"is_master"`
+	Endpoints        []string  `json:"endpoints"`
+	MasterZone       string    `json:"master_zone"`
+	Zones            []rgwZone `json:"zones"`
+	PlacementTargets []struct {
+		Name           string   `json:"name"`
+		StorageClasses []string `json:"storage_classes"`
+	} `json:"placement_targets"`
+	DefaultPlacement string `json:"default_placement"`
+	RealmID          string `json:"realm_id"`
+}
+
+// rgwPeriod is the current period of the realm returned by the RGW admin API.
+type rgwPeriod struct {
+	ID        string `json:"id"`
+	Epoch     uint64 `json:"epoch"`
+	PeriodMap struct {
+		ID         string         `json:"id"`
+		Zonegroups []rgwZoneGroup `json:"zonegroups"`
+	} `json:"period_map"`
+	MasterZonegroup string `json:"master_zonegroup"`
+	MasterZone      string `json:"master_zone"`
+	RealmID         string `json:"realm_id"`
+	RealmName       string `json:"realm_name"`
+	RealmEpoch      uint64 `json:"realm_epoch"`
+}
+
+func rgwGetLocalZone(ctx context.Context, api *admin.API) (*rgwZone, error) {
+	zone := &rgwZone{}
+	if err := rgwAdminGet(ctx, api, "/config", url.Values{
+		"type": []string{"zone"},
+	}, zone); err != nil {
+		return nil, fmt.Errorf("failed to get local zone config. %w", err)
+	}
+
+	return zone, nil
+}
+
+func rgwGetPeriod(ctx context.Context, api *admin.API) (*rgwPeriod, error) {
+	period := &rgwPeriod{}
+	if err := rgwAdminGet(ctx, api, "/realm/period", nil, period); err != nil {
+		return nil, fmt.Errorf("failed to get current period. %w", err)
+	}
+
+	return period, nil
+}
+
+// zoneGroupOf returns the zonegroup the given zone id belongs to.
+func (p *rgwPeriod) zoneGroupOf(zoneID string) *rgwZoneGroup {
+	for i := range p.PeriodMap.Zonegroups {
+		for _, zone := range p.PeriodMap.Zonegroups[i].Zones {
+			if zone.ID == zoneID {
+				return &p.PeriodMap.Zonegroups[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// zone returns the zone with the given id from any of the period's zonegroups.
+func (p *rgwPeriod) zone(zoneID string) *rgwZone {
+	if zg := p.zoneGroupOf(zoneID); zg != nil {
+		for i := range zg.Zones {
+			if zg.Zones[i].ID == zoneID {
+				return &zg.Zones[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseCephTime parses the timestamps (`utime_t`) returned by the RGW admin API.
+// Unset (zero) timestamps are returned as not ok.
+func parseCephTime(s string) (time.Time, bool) {
+	for _, layout := range []string{
+		"2006-01-02T15:04:05.999999999Z",
+		"2006-01-02 15:04:05.999999999Z",
+		"2006-01-02T15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999",
+	} {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			if t.Unix() <= 0 {
+				return time.Time{}, false
+			}
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
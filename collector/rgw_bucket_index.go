@@ -0,0 +1,128 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+type RGWBucketIndex struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rgw_bucket_index"] = NewRGWBucketIndex
+}
+
+func NewRGWBucketIndex() (Collector, error) {
+	return &RGWBucketIndex{}, nil
+}
+
+func (c *RGWBucketIndex) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	buckets, err := client.RGWAdminAPI.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+
+	maxObjectsPerShard := client.Config.RGW.BucketIndex.MaxObjectsPerShard
+
+	var errs error
+	for _, bucketName := range buckets {
+		bucketInfo, err := client.RGWAdminAPI.GetBucketInfo(ctx, admin.Bucket{
+			Bucket: bucketName,
+		})
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get bucket %q info. %w", bucketName, err))
+			continue
+		}
+
+		labels := map[string]string{
+			"bucket": bucketName,
+			"uid":    bucketInfo.Owner,
+			"realm":  client.Name,
+		}
+
+		// Add tenant as label when set
+		if bucketInfo.Tenant != "" {
+			labels["tenant"] = bucketInfo.Tenant
+		}
+
+		infoLabels := map[string]string{
+			"id":             bucketInfo.ID,
+			"marker":         bucketInfo.Marker,
+			"placement_rule": bucketInfo.PlacementRule,
+			"zonegroup":      bucketInfo.Zonegroup,
+			"index_type":     bucketInfo.IndexType,
+		}
+		for k, v := range labels {
+			infoLabels[k] = v
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_index_info"),
+			"RGW Bucket Index info",
+			nil, infoLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, 1)
+
+		// Indexless buckets don't have any index shards
+		if strings.EqualFold(bucketInfo.IndexType, "Indexless") {
+			continue
+		}
+
+		// A bucket with zero shards has a single (unsharded) index object
+		shards := uint64(1)
+		if bucketInfo.NumShards != nil && *bucketInfo.NumShards > 0 {
+			shards = *bucketInfo.NumShards
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_index_shards"),
+			"RGW Bucket Index number of shards",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(shards))
+
+		// The admin API doesn't return per shard stats, so the objects are
+		// assumed to be evenly distributed over the shards
+		objectsPerShard := uint64PtrToFloat(bucketInfo.Usage.RgwMain.NumObjects) / float64(shards)
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_index_objects_per_shard"),
+			"RGW Bucket Index average objects per shard",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, objectsPerShard)
+
+		if maxObjectsPerShard > 0 {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_index_shard_fill_ratio"),
+				"RGW Bucket Index objects per shard ratio against the configured max objects per shard",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, objectsPerShard/float64(maxObjectsPerShard))
+		}
+	}
+
+	return errs
+}
@@ -19,12 +19,19 @@ package collector
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/ceph/go-ceph/rgw/admin"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/multierr"
 )
 
+// rgwBucketCategories is the usage of all categories of the bucket info returned by the RGW admin API,
+// the go-ceph `admin.BucketUsage` only contains a fixed set of categories.
+type rgwBucketCategories struct {
+	Categories map[string]admin.RgwUsage `json:"usage"`
+}
+
 type RGWBuckets struct {
 	current *prometheus.Desc
 }
@@ -124,25 +131,150 @@ func (c *RGWBuckets) Update(ctx context.Context, client *Client, ch chan<- prome
 				c.current, prometheus.GaugeValue, float64(*bucketInfo.Usage.RgwMain.NumObjects))
 		}
 
-		if bucketInfo.BucketQuota.Enabled == nil || !*bucketInfo.BucketQuota.Enabled {
-			continue
+		if client.Config.RGW.Buckets.Categories {
+			if err := c.updateCategories(ctx, client, bucketName, labels, ch); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to get bucket %q usage categories. %w", bucketName, err))
+			}
+		}
+
+		c.updateQuota(bucketInfo, labels, ch)
+	}
+
+	return errs
+}
+
+// updateCategories emits the usage of each category (e.g., `rgw.main`, `rgw.multimeta` for
+// incomplete multipart uploads), the `bucket_size` metrics only contain the `rgw.main` category.
+func (c *RGWBuckets) updateCategories(ctx context.Context, client *Client, bucketName string, labels map[string]string, ch chan<- prometheus.Metric) error {
+	bucket := &rgwBucketCategories{}
+	if err := rgwAdminGet(ctx, client.RGWAdminAPI, "/bucket", url.Values{
+		"bucket": []string{bucketName},
+	}, bucket); err != nil {
+		return err
+	}
+
+	for category, usage := range bucket.Categories {
+		categoryLabels := map[string]string{
+			"category": category,
+		}
+		for k, v := range labels {
+			categoryLabels[k] = v
 		}
 
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_category_size"),
+			"RGW Bucket Size per usage category",
+			nil, categoryLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, uint64PtrToFloat(usage.Size))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_category_size_actual"),
+			"RGW Bucket Size actual per usage category",
+			nil, categoryLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, uint64PtrToFloat(usage.SizeActual))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_category_num_objects"),
+			"RGW Bucket Num Objects per usage category",
+			nil, categoryLabels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, uint64PtrToFloat(usage.NumObjects))
+	}
+
+	return nil
+}
+
+func (c *RGWBuckets) updateQuota(bucketInfo admin.Bucket, labels map[string]string, ch chan<- prometheus.Metric) {
+	quota := bucketInfo.BucketQuota
+
+	enabled := 0.0
+	if quota.Enabled != nil && *quota.Enabled {
+		enabled = 1
+	}
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_quota_enabled"),
+		"RGW Bucket Quota enabled",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, enabled)
+
+	if enabled == 0 {
+		return
+	}
+
+	// Max size is preferred over the max size KiB as it is not rounded
+	var maxSize *int64
+	if quota.MaxSize != nil {
+		maxSize = quota.MaxSize
+	} else if quota.MaxSizeKb != nil {
+		size := int64(*quota.MaxSizeKb) * 1024
+		maxSize = &size
+	}
+
+	// RGW checks the size quota against the actual (rounded) size, unless `check_on_raw` is set
+	size := bucketInfo.Usage.RgwMain.SizeActual
+	if quota.CheckOnRaw {
+		size = bucketInfo.Usage.RgwMain.Size
+	}
+
+	c.updateQuotaLimit("size", maxSize, size, labels, ch)
+	c.updateQuotaLimit("objects", quota.MaxObjects, bucketInfo.Usage.RgwMain.NumObjects, labels, ch)
+
+	if quota.MaxSizeKb != nil && *quota.MaxSizeKb >= 0 {
 		c.current = prometheus.NewDesc(
 			prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_quota_max_size_kb"),
 			"RGW Bucket Quota Max Size KiB",
 			nil, labels)
 		ch <- prometheus.MustNewConstMetric(
-			c.current, prometheus.GaugeValue, float64(*bucketInfo.BucketQuota.MaxSizeKb))
+			c.current, prometheus.GaugeValue, float64(*quota.MaxSizeKb))
+	}
 
+	if quota.MaxObjects != nil && *quota.MaxObjects >= 0 {
 		c.current = prometheus.NewDesc(
 			prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_quota_max_objects"),
 			"RGW Bucket Quota Max Objects",
 			nil, labels)
 		ch <- prometheus.MustNewConstMetric(
-			c.current, prometheus.GaugeValue, float64(*bucketInfo.BucketQuota.MaxObjects))
+			c.current, prometheus.GaugeValue, float64(*quota.MaxObjects))
+	}
+}
 
+// updateQuotaLimit emits whether the quota limit is unlimited and, if limited, the used ratio.
+// Nothing is emitted when the limit is missing from the API response.
+func (c *RGWBuckets) updateQuotaLimit(quotaType string, max *int64, used *uint64, labels map[string]string, ch chan<- prometheus.Metric) {
+	if max == nil {
+		return
 	}
 
-	return errs
+	quotaLabels := map[string]string{
+		"quota": quotaType,
+	}
+	for k, v := range labels {
+		quotaLabels[k] = v
+	}
+
+	// Negative max values mean the quota is unlimited
+	unlimited := 0.0
+	if *max < 0 {
+		unlimited = 1
+	}
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_quota_unlimited"),
+		"RGW Bucket Quota unlimited",
+		nil, quotaLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, unlimited)
+
+	if *max <= 0 || used == nil {
+		return
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "bucket_quota_used_ratio"),
+		"RGW Bucket Quota used ratio",
+		nil, quotaLabels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(*used)/float64(*max))
 }
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// rgwRateLimit is a user, bucket or global rate limit returned by the RGW admin API.
+// The go-ceph `admin.RateLimitSpec` is only available with the `ceph_preview` build tag.
+type rgwRateLimit struct {
+	Enabled       *bool  `json:"enabled"`
+	MaxReadOps    *int64 `json:"max_read_ops"`
+	MaxWriteOps   *int64 `json:"max_write_ops"`
+	MaxReadBytes  *int64 `json:"max_read_bytes"`
+	MaxWriteBytes *int64 `json:"max_write_bytes"`
+	MaxListOps    *int64 `json:"max_list_ops"`
+	MaxDeleteOps  *int64 `json:"max_delete_ops"`
+}
+
+type rgwRateLimits struct {
+	UserRateLimit      rgwRateLimit `json:"user_ratelimit"`
+	BucketRateLimit    rgwRateLimit `json:"bucket_ratelimit"`
+	AnonymousRateLimit rgwRateLimit `json:"anonymous_ratelimit"`
+}
+
+type RGWRateLimit struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rgw_ratelimit"] = NewRGWRateLimit
+}
+
+func NewRGWRateLimit() (Collector, error) {
+	return &RGWRateLimit{}, nil
+}
+
+func (c *RGWRateLimit) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	var errs error
+
+	global := &rgwRateLimits{}
+	if err := rgwAdminGet(ctx, client.RGWAdminAPI, "/ratelimit", url.Values{
+		"global": []string{"true"},
+	}, global); err != nil {
+		errs = multierr.Append(errs, fmt.Errorf("failed to get global rate limits. %w", err))
+	} else {
+		for scope, limits := range map[string]rgwRateLimit{
+			"user":      global.UserRateLimit,
+			"bucket":    global.BucketRateLimit,
+			"anonymous": global.AnonymousRateLimit,
+		} {
+			c.emitRateLimit("ratelimit_global", limits, map[string]string{
+				"scope": scope,
+				"realm": client.Name,
+			}, ch)
+		}
+	}
+
+	users, err := client.RGWAdminAPI.GetUsers(ctx)
+	if err != nil {
+		return multierr.Append(errs, err)
+	}
+
+	for _, user := range *users {
+		limits := &rgwRateLimits{}
+		if err := rgwAdminGet(ctx, client.RGWAdminAPI, "/ratelimit", url.Values{
+			"ratelimit-scope": []string{"user"},
+			"uid":             []string{user},
+		}, limits); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get user %q rate limit. %w", user, err))
+			continue
+		}
+
+		// Only emit enabled rate limits to keep the number of series low
+		if limits.UserRateLimit.Enabled == nil || !*limits.UserRateLimit.Enabled {
+			continue
+		}
+
+		c.emitRateLimit("user_ratelimit", limits.UserRateLimit, map[string]string{
+			"uid":   user,
+			"realm": client.Name,
+		}, ch)
+	}
+
+	buckets, err := client.RGWAdminAPI.ListBuckets(ctx)
+	if err != nil {
+		return multierr.Append(errs, err)
+	}
+
+	for _, bucketName := range buckets {
+		limits := &rgwRateLimits{}
+		if err := rgwAdminGet(ctx, client.RGWAdminAPI, "/ratelimit", url.Values{
+			"ratelimit-scope": []string{"bucket"},
+			"bucket":          []string{bucketName},
+		}, limits); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get bucket %q rate limit. %w", bucketName, err))
+			continue
+		}
+
+		// Only emit enabled rate limits to keep the number of series low
+		if limits.BucketRateLimit.Enabled == nil || !*limits.BucketRateLimit.Enabled {
+			continue
+		}
+
+		c.emitRateLimit("bucket_ratelimit", limits.BucketRateLimit, map[string]string{
+			"bucket": bucketName,
+			"realm":  client.Name,
+		}, ch)
+	}
+
+	return errs
+}
+
+func (c *RGWRateLimit) emitRateLimit(name string, limits rgwRateLimit, labels map[string]string, ch chan<- prometheus.Metric) {
+	enabled := 0.0
+	if limits.Enabled != nil && *limits.Enabled {
+		enabled = 1
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", name+"_enabled"),
+		"RGW Rate Limit enabled",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, enabled)
+
+	for suffix, value := range map[string]*int64{
+		"max_read_ops":    limits.MaxReadOps,
+		"max_write_ops":   limits.MaxWriteOps,
+		"max_read_bytes":  limits.MaxReadBytes,
+		"max_write_bytes": limits.MaxWriteBytes,
+		"max_list_ops":    limits.MaxListOps,
+		"max_delete_ops":  limits.MaxDeleteOps,
+	} {
+		// List and delete ops limits are only available with newer Ceph versions
+		if value == nil {
+			continue
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", name+"_"+suffix),
+			"RGW Rate Limit "+suffix+" per minute per RGW (0 = unlimited)",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(*value))
+	}
+}
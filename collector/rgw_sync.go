@@ -0,0 +1,439 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// rgwSyncStatus is the metadata or data sync status of the local zone.
+type rgwSyncStatus struct {
+	Info struct {
+		Status    string `json:"status"`
+		NumShards uint32 `json:"num_shards"`
+		Period    string `json:"period"`
+	} `json:"info"`
+	Markers []struct {
+		Key uint32        `json:"key"`
+		Val rgwSyncMarker `json:"val"`
+	} `json:"markers"`
+}
+
+type rgwSyncMarker struct {
+	// Data sync markers contain the state as a string
+	Status string `json:"status"`
+	// Metadata sync markers contain the state as a number
+	State     *int   `json:"state"`
+	Marker    string `json:"marker"`
+	Timestamp string `json:"timestamp"`
+}
+
+func (m rgwSyncMarker) incremental() bool {
+	if m.Status != "" {
+		return m.Status == "incremental-sync"
+	}
+
+	return m.State != nil && *m.State == 1
+}
+
+// rgwLogShardInfo is the info of a metadata or data log shard of the source zone.
+type rgwLogShardInfo struct {
+	Marker     string `json:"marker"`
+	LastUpdate string `json:"last_update"`
+}
+
+type rgwBucketShardSyncInfo struct {
+	Status    string `json:"status"`
+	IncMarker struct {
+		Position  string `json:"position"`
+		Timestamp string `json:"timestamp"`
+	} `json:"inc_marker"`
+}
+
+type RGWSync struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rgw_sync"] = NewRGWSync
+}
+
+func NewRGWSync() (Collector, error) {
+	return &RGWSync{}, nil
+}
+
+func (c *RGWSync) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	localZone, err := rgwGetLocalZone(ctx, client.RGWAdminAPI)
+	if err != nil {
+		return err
+	}
+
+	period, err := rgwGetPeriod(ctx, client.RGWAdminAPI)
+	if err != nil {
+		return err
+	}
+
+	zoneGroup := period.zoneGroupOf(localZone.ID)
+	if zoneGroup == nil {
+		return fmt.Errorf("local zone %q not found in current period %s", localZone.Name, period.ID)
+	}
+
+	var errs error
+
+	// The metadata master zone doesn't sync metadata from other zones
+	if period.MasterZone != localZone.ID {
+		if err := c.updateMetadataSync(ctx, client, localZone, period, ch); err != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+
+	for _, sourceZone := range zoneGroup.Zones {
+		if !syncsFrom(localZone, zoneGroup, &sourceZone) {
+			continue
+		}
+
+		if err := c.updateDataSync(ctx, client, localZone, &sourceZone, ch); err != nil {
+			errs = multierr.Append(errs, err)
+		}
+
+		for _, bucket := range client.Config.RGW.Sync.Buckets {
+			if err := c.updateBucketSync(ctx, client, localZone, &sourceZone, bucket, ch); err != nil {
+				errs = multierr.Append(errs, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+func (c *RGWSync) updateMetadataSync(ctx context.Context, client *Client, localZone *rgwZone, period *rgwPeriod, ch chan<- prometheus.Metric) error {
+	status := &rgwSyncStatus{}
+	if err := rgwAdminGet(ctx, client.RGWAdminAPI, "/log", url.Values{
+		"type":   []string{"metadata"},
+		"status": []string{""},
+	}, status); err != nil {
+		// No metadata sync is running on this zone
+		if isRGWAdminNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get metadata sync status. %w", err)
+	}
+
+	labels := map[string]string{
+		"realm": client.Name,
+		"zone":  localZone.Name,
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "sync_metadata_info"),
+		"RGW Metadata Sync info",
+		nil, map[string]string{
+			"realm":  client.Name,
+			"zone":   localZone.Name,
+			"status": status.Info.Status,
+			"period": status.Info.Period,
+		})
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	var remote *admin.API
+	if masterZone := period.zone(period.MasterZone); masterZone != nil {
+		remote = rgwAPIForZone(client, masterZone)
+	}
+
+	return c.emitShardStatus(ctx, "metadata", remote, url.Values{
+		"type":   []string{"metadata"},
+		"period": []string{status.Info.Period},
+	}, status, labels, ch)
+}
+
+func (c *RGWSync) updateDataSync(ctx context.Context, client *Client, localZone *rgwZone, sourceZone *rgwZone, ch chan<- prometheus.Metric) error {
+	status := &rgwSyncStatus{}
+	if err := rgwAdminGet(ctx, client.RGWAdminAPI, "/log", url.Values{
+		"type":        []string{"data"},
+		"status":      []string{""},
+		"source-zone": []string{sourceZone.ID},
+	}, status); err != nil {
+		return fmt.Errorf("failed to get data sync status from source zone %q. %w", sourceZone.Name, err)
+	}
+
+	labels := map[string]string{
+		"realm":       client.Name,
+		"zone":        localZone.Name,
+		"source_zone": sourceZone.Name,
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "sync_data_info"),
+		"RGW Data Sync info",
+		nil, map[string]string{
+			"realm":       client.Name,
+			"zone":        localZone.Name,
+			"source_zone": sourceZone.Name,
+			"status":      status.Info.Status,
+		})
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	return c.emitShardStatus(ctx, "data", rgwAPIForZone(client, sourceZone), url.Values{
+		"type": []string{"data"},
+	}, status, labels, ch)
+}
+
+// emitShardStatus emits the shard metrics of a sync status, if a remote API is given the
+// sync markers are compared against the log shards of the source zone to find shards behind.
+func (c *RGWSync) emitShardStatus(ctx context.Context, syncType string, remote *admin.API, remoteArgs url.Values, status *rgwSyncStatus, labels map[string]string, ch chan<- prometheus.Metric) error {
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "sync_"+syncType+"_shards"),
+		"RGW Sync number of "+syncType+" log shards",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(status.Info.NumShards))
+
+	var errs error
+	fullSync := 0
+	behind := 0
+	var oldest float64
+	for _, m := range status.Markers {
+		if !m.Val.incremental() {
+			fullSync++
+			behind++
+			continue
+		}
+
+		if remote == nil {
+			continue
+		}
+
+		args := url.Values{}
+		for k, v := range remoteArgs {
+			args[k] = v
+		}
+		args.Set("id", strconv.FormatUint(uint64(m.Key), 10))
+		args.Set("info", "")
+
+		shardInfo := &rgwLogShardInfo{}
+		if err := rgwAdminGet(ctx, remote, "/log", args, shardInfo); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get %s log shard %d info from %s. %w", syncType, m.Key, remote.Endpoint, err))
+			continue
+		}
+
+		// Sync markers are ordered, so a lower local marker means changes haven't been applied yet
+		if rgwCompareMarkers(m.Val.Marker, shardInfo.Marker) >= 0 {
+			continue
+		}
+		behind++
+
+		if ts, ok := parseCephTime(m.Val.Timestamp); ok {
+			if oldest == 0 || float64(ts.Unix()) < oldest {
+				oldest = float64(ts.Unix())
+			}
+		}
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "sync_"+syncType+"_shards_full_sync"),
+		"RGW Sync number of "+syncType+" log shards in full sync",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(fullSync))
+
+	// Without access to the source zone, only the shards in full sync are known to be behind
+	if remote == nil {
+		return errs
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "sync_"+syncType+"_shards_behind"),
+		"RGW Sync number of "+syncType+" log shards behind the source zone",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(behind))
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "sync_"+syncType+"_oldest_incremental_change_timestamp_seconds"),
+		"RGW Sync timestamp of the oldest last synced incremental change of the "+syncType+" log shards behind (0 = none behind)",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, oldest)
+
+	return errs
+}
+
+func (c *RGWSync) updateBucketSync(ctx context.Context, client *Client, localZone *rgwZone, sourceZone *rgwZone, bucket string, ch chan<- prometheus.Metric) error {
+	shards := []rgwBucketShardSyncInfo{}
+	if err := rgwAdminGet(ctx, client.RGWAdminAPI, "/log", url.Values{
+		"type":        []string{"bucket-index"},
+		"status":      []string{""},
+		"bucket":      []string{bucket},
+		"source-zone": []string{sourceZone.ID},
+	}, &shards); err != nil {
+		return fmt.Errorf("failed to get bucket %q sync status from source zone %q. %w", bucket, sourceZone.Name, err)
+	}
+
+	labels := map[string]string{
+		"realm":       client.Name,
+		"zone":        localZone.Name,
+		"source_zone": sourceZone.Name,
+		"bucket":      bucket,
+	}
+
+	incremental := 0
+	var oldest float64
+	for _, shard := range shards {
+		if shard.Status != "incremental-sync" {
+			continue
+		}
+		incremental++
+
+		if ts, ok := parseCephTime(shard.IncMarker.Timestamp); ok {
+			if oldest == 0 || float64(ts.Unix()) < oldest {
+				oldest = float64(ts.Unix())
+			}
+		}
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "sync_bucket_shards"),
+		"RGW Bucket Sync number of bucket index shards",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(len(shards)))
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "sync_bucket_shards_incremental"),
+		"RGW Bucket Sync number of bucket index shards in incremental sync",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(incremental))
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "sync_bucket_oldest_incremental_change_timestamp_seconds"),
+		"RGW Bucket Sync timestamp of the oldest last synced incremental change of the bucket index shards",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, oldest)
+
+	return nil
+}
+
+// syncsFrom returns true when the local zone syncs data from the given source zone.
+func syncsFrom(localZone *rgwZone, zoneGroup *rgwZoneGroup, sourceZone *rgwZone) bool {
+	if sourceZone.ID == localZone.ID {
+		return false
+	}
+
+	// Only RGW and archive zones export their data
+	if sourceZone.TierType != "" && sourceZone.TierType != "rgw" && sourceZone.TierType != "archive" {
+		return false
+	}
+
+	// Use the local zone from the period as it contains the sync settings
+	for _, zone := range zoneGroup.Zones {
+		if zone.ID != localZone.ID {
+			continue
+		}
+
+		if zone.SyncFromAll == nil || *zone.SyncFromAll {
+			return true
+		}
+
+		return slices.Contains(zone.SyncFrom, sourceZone.Name)
+	}
+
+	return false
+}
+
+// rgwAPIForZone returns an admin API client for the zone's endpoint, the endpoint can be
+// overridden in the config. Returns nil when no endpoint is known for the zone.
+func rgwAPIForZone(client *Client, zone *rgwZone) *admin.API {
+	endpoint := ""
+	if idx := slices.IndexFunc(client.Config.RGW.Sync.ZoneEndpoints, func(ze *config.RGWZoneEndpoint) bool {
+		return ze.Name == zone.Name
+	}); idx > -1 {
+		endpoint = client.Config.RGW.Sync.ZoneEndpoints[idx].Endpoint
+	} else if len(zone.Endpoints) > 0 {
+		endpoint = zone.Endpoints[0]
+	}
+
+	if endpoint == "" {
+		return nil
+	}
+
+	// Zones of a realm share the (admin) users, so the same credentials can be used
+	remote := *client.RGWAdminAPI
+	remote.Endpoint = endpoint
+	return &remote
+}
+
+// rgwCompareMarkers compares two log markers, e.g., `1_1767225600.000000_123.1` (omap) or
+// `00000000000000000001:00000000000000000123` (FIFO). The numeric parts are compared by value,
+// so markers with a different zero padding or number of digits are compared correctly.
+func rgwCompareMarkers(a string, b string) int {
+	for a != "" && b != "" {
+		aPart, aNum := rgwMarkerPart(a)
+		bPart, bNum := rgwMarkerPart(b)
+		a = a[len(aPart):]
+		b = b[len(bPart):]
+
+		if aNum && bNum {
+			aPart = strings.TrimLeft(aPart, "0")
+			bPart = strings.TrimLeft(bPart, "0")
+			// Without leading zeros the longer number is the larger one
+			if c := cmp.Compare(len(aPart), len(bPart)); c != 0 {
+				return c
+			}
+		}
+
+		if c := strings.Compare(aPart, bPart); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(a), len(b))
+}
+
+// rgwMarkerPart returns the leading run of digits or non-digits of the marker and whether it is numeric.
+func rgwMarkerPart(marker string) (string, bool) {
+	numeric := marker[0] >= '0' && marker[0] <= '9'
+	end := strings.IndexFunc(marker, func(r rune) bool {
+		return (r >= '0' && r <= '9') != numeric
+	})
+	if end == -1 {
+		end = len(marker)
+	}
+
+	return marker[:end], numeric
+}
+
+func isRGWAdminNotFound(err error) bool {
+	apiErr := &rgwAdminError{}
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/galexrt/extended-ceph-exporter/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// rgwTestRoute is a recorded RGW admin API response, the route matches when the path
+// matches and all of the route's query args are set to the given values.
+type rgwTestRoute struct {
+	path   string
+	query  map[string]string
+	status int
+	body   string
+}
+
+// newRGWTestServer returns a stand-in RGW admin API serving the given routes, `{{endpoint}}`
+// in a response body is replaced by the server's own endpoint. Unknown requests return a 404.
+func newRGWTestServer(t *testing.T, routes []rgwTestRoute) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+	routes:
+		for _, route := range routes {
+			if route.path != r.URL.Path {
+				continue
+			}
+			for k, v := range route.query {
+				if !query.Has(k) || query.Get(k) != v {
+					continue routes
+				}
+			}
+
+			status := route.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(strings.ReplaceAll(route.body, "{{endpoint}}", "http://"+r.Host)))
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"Code":"NoSuchKey"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+const (
+	rgwTestLocalZone = `{"id":"zone-b","name":"b","realm_id":"realm-1"}`
+
+	// Zone b syncs from the metadata master zone a, zone c is a cloud sync zone which doesn't export data
+	rgwTestPeriod = `{
+  "id": "period-1",
+  "epoch": 3,
+  "master_zonegroup": "zg-1",
+  "master_zone": "zone-a",
+  "realm_id": "realm-1",
+  "realm_name": "test",
+  "period_map": {
+    "id": "period-1",
+    "zonegroups": [{
+      "id": "zg-1",
+      "name": "default",
+      "is_master": true,
+      "master_zone": "zone-a",
+      "zones": [
+        {"id": "zone-a", "name": "a", "endpoints": ["{{endpoint}}"]},
+        {"id": "zone-b", "name": "b", "endpoints": [], "sync_from_all": true, "sync_from": []},
+        {"id": "zone-c", "name": "c", "tier_type": "cloud-s3", "endpoints": []}
+      ]
+    }]
+  }
+}`
+
+	rgwTestMetadataSyncStatus = `{
+  "info": {"status": "sync", "num_shards": 2, "period": "p1"},
+  "markers": [
+    {"key": 0, "val": {"state": 1, "marker": "1_99", "timestamp": "2026-01-01T00:00:00.000000Z"}},
+    {"key": 1, "val": {"state": 0, "marker": "", "timestamp": "0.000000"}}
+  ]
+}`
+
+	rgwTestDataSyncStatus = `{
+  "info": {"status": "sync", "num_shards": 2},
+  "markers": [
+    {"key": 0, "val": {"status": "incremental-sync", "marker": "00000000001.1.5", "timestamp": "2026-01-02T00:00:00.000000Z"}},
+    {"key": 1, "val": {"status": "incremental-sync", "marker": "00000000002.1.5", "timestamp": "2026-01-03T00:00:00.000000Z"}}
+  ]
+}`
+)
+
+func rgwTestRoutes() []rgwTestRoute {
+	return []rgwTestRoute{
+		{path: "/admin/config", query: map[string]string{"type": "zone"}, body: rgwTestLocalZone},
+		{path: "/admin/realm/period", body: rgwTestPeriod},
+		{path: "/admin/log", query: map[string]string{"type": "metadata", "status": ""}, body: rgwTestMetadataSyncStatus},
+		{path: "/admin/log", query: map[string]string{"type": "metadata", "period": "p1", "id": "0", "info": ""}, body: `{"marker":"1_100","last_update":"2026-01-04T00:00:00.000000Z"}`},
+		{path: "/admin/log", query: map[string]string{"type": "data", "status": "", "source-zone": "zone-a"}, body: rgwTestDataSyncStatus},
+		{path: "/admin/log", query: map[string]string{"type": "data", "id": "0", "info": ""}, body: `{"marker":"1.1.5","last_update":"2026-01-02T00:00:00.000000Z"}`},
+		{path: "/admin/log", query: map[string]string{"type": "data", "id": "1", "info": ""}, body: `{"marker":"00000000003.1.5","last_update":"2026-01-04T00:00:00.000000Z"}`},
+	}
+}
+
+func TestRGWSyncUpdate(t *testing.T) {
+	metadataLabels := `{realm="test",zone="b"}`
+	dataLabels := `{realm="test",source_zone="a",zone="b"}`
+
+	metadataMetrics := map[string]float64{
+		`ceph_rgw_sync_metadata_info{period="p1",realm="test",status="sync",zone="b"}`: 1,
+		"ceph_rgw_sync_metadata_shards" + metadataLabels:                               2,
+		"ceph_rgw_sync_metadata_shards_full_sync" + metadataLabels:                     1,
+		// Shard 1 is in full sync, shard 0 is behind the master zone's log (the markers have a different number of digits)
+		"ceph_rgw_sync_metadata_shards_behind" + metadataLabels:                               2,
+		"ceph_rgw_sync_metadata_oldest_incremental_change_timestamp_seconds" + metadataLabels: 1767225600,
+	}
+
+	tests := []struct {
+		name    string
+		routes  []rgwTestRoute
+		want    map[string]float64
+		wantErr func(t *testing.T, err error)
+	}{
+		{
+			name:   "metadata and data sync",
+			routes: rgwTestRoutes(),
+			want: mergeMetrics(metadataMetrics, map[string]float64{
+				`ceph_rgw_sync_data_info{realm="test",source_zone="a",status="sync",zone="b"}`: 1,
+				"ceph_rgw_sync_data_shards" + dataLabels:                                       2,
+				"ceph_rgw_sync_data_shards_full_sync" + dataLabels:                             0,
+				// Shard 0 is caught up (the markers have a different zero padding), shard 1 is behind the source zone's log
+				"ceph_rgw_sync_data_shards_behind" + dataLabels:                               1,
+				"ceph_rgw_sync_data_oldest_incremental_change_timestamp_seconds" + dataLabels: 1767398400,
+			}),
+		},
+		{
+			name: "access denied",
+			routes: append([]rgwTestRoute{
+				{path: "/admin/config", status: http.StatusForbidden, body: `{"Code":"AccessDenied"}`},
+			}, rgwTestRoutes()...),
+			want: map[string]float64{},
+			wantErr: func(t *testing.T, err error) {
+				if !errors.Is(err, admin.ErrAccessDenied) {
+					t.Errorf("expected access denied error, got %v", err)
+				}
+
+				apiErr := &rgwAdminError{}
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+					t.Errorf("expected rgw admin error with status %d, got %v", http.StatusForbidden, err)
+				}
+			},
+		},
+		{
+			name: "source zone log shard access denied",
+			routes: append([]rgwTestRoute{
+				{path: "/admin/log", query: map[string]string{"type": "data", "id": "1", "info": ""}, status: http.StatusForbidden, body: `{"Code":"AccessDenied"}`},
+			}, rgwTestRoutes()...),
+			want: mergeMetrics(metadataMetrics, map[string]float64{
+				`ceph_rgw_sync_data_info{realm="test",source_zone="a",status="sync",zone="b"}`: 1,
+				"ceph_rgw_sync_data_shards" + dataLabels:                                       2,
+				"ceph_rgw_sync_data_shards_full_sync" + dataLabels:                             0,
+				// The unknown shard isn't counted as behind
+				"ceph_rgw_sync_data_shards_behind" + dataLabels:                               0,
+				"ceph_rgw_sync_data_oldest_incremental_change_timestamp_seconds" + dataLabels: 0,
+			}),
+			wantErr: func(t *testing.T, err error) {
+				if !errors.Is(err, admin.ErrAccessDenied) {
+					t.Errorf("expected access denied error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := newRGWTestServer(t, test.routes)
+
+			api, err := admin.New(srv.URL, "access", "secret", srv.Client())
+			if err != nil {
+				t.Fatalf("failed to create rgw admin api client. %v", err)
+			}
+
+			cfg, _, err := config.LoadTestConfig()
+			if err != nil {
+				t.Fatalf("failed to load test config. %v", err)
+			}
+
+			client := &Client{
+				Name:        "test",
+				Config:      cfg,
+				RGWAdminAPI: api,
+			}
+
+			c, err := NewRGWSync()
+			if err != nil {
+				t.Fatalf("failed to create collector. %v", err)
+			}
+
+			got, err := gatherMetrics(t, func(ch chan<- prometheus.Metric) error {
+				return c.Update(context.Background(), client, ch)
+			})
+			if test.wantErr != nil {
+				test.wantErr(t, err)
+			} else if err != nil {
+				t.Errorf("unexpected error. %v", err)
+			}
+
+			compareMetrics(t, test.want, got)
+		})
+	}
+}
+
+func TestRGWCompareMarkers(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "1_100", b: "1_100", want: 0},
+		{a: "1_99", b: "1_100", want: -1},
+		{a: "1_100", b: "1_99", want: 1},
+		{a: "1_1767225600.000000_99.1", b: "1_1767225600.000000_100.1", want: -1},
+		{a: "1_1767225601.000000_1.1", b: "1_1767225600.000000_100.1", want: 1},
+		{a: "00000000000000000001:00000000000000000123", b: "1:123", want: 0},
+		{a: "00000000000000000001:00000000000000000123", b: "00000000000000000002:00000000000000000001", want: -1},
+		{a: "", b: "1_1", want: -1},
+		{a: "1_1", b: "", want: 1},
+		{a: "1_1", b: "1_1.1", want: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+" "+test.b, func(t *testing.T) {
+			if got := rgwCompareMarkers(test.a, test.b); got != test.want {
+				t.Errorf("rgwCompareMarkers(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSyncsFrom(t *testing.T) {
+	syncFromAll := true
+	syncFromSome := false
+
+	zoneGroup := &rgwZoneGroup{
+		Zones: []rgwZone{
+			{ID: "zone-a", Name: "a"},
+			{ID: "zone-b", Name: "b", SyncFromAll: &syncFromSome, SyncFrom: []string{"a"}},
+			{ID: "zone-c", Name: "c", TierType: "cloud-s3"},
+			{ID: "zone-d", Name: "d", TierType: "archive"},
+			{ID: "zone-e", Name: "e", SyncFromAll: &syncFromAll},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		localZone  string
+		sourceZone string
+		want       bool
+	}{
+		{name: "local zone", localZone: "zone-a", sourceZone: "zone-a", want: false},
+		{name: "sync from all unset", localZone: "zone-a", sourceZone: "zone-d", want: true},
+		{name: "sync from all", localZone: "zone-e", sourceZone: "zone-a", want: true},
+		{name: "sync from listed zone", localZone: "zone-b", sourceZone: "zone-a", want: true},
+		{name: "sync from unlisted zone", localZone: "zone-b", sourceZone: "zone-e", want: false},
+		{name: "cloud sync zone", localZone: "zone-e", sourceZone: "zone-c", want: false},
+		{name: "archive zone", localZone: "zone-e", sourceZone: "zone-d", want: true},
+		{name: "local zone not in zonegroup", localZone: "zone-x", sourceZone: "zone-a", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			localZone := &rgwZone{ID: test.localZone}
+			sourceZone := &zoneGroup.Zones[0]
+			for i := range zoneGroup.Zones {
+				if zoneGroup.Zones[i].ID == test.sourceZone {
+					sourceZone = &zoneGroup.Zones[i]
+				}
+			}
+
+			if got := syncsFrom(localZone, zoneGroup, sourceZone); got != test.want {
+				t.Errorf("syncsFrom(%s, %s) = %v, want %v", test.localZone, test.sourceZone, got, test.want)
+			}
+		})
+	}
+}
+
+func mergeMetrics(maps ...map[string]float64) map[string]float64 {
+	out := map[string]float64{}
+	for _, m := range maps {
+		for k, v := range m {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type RGWTopology struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rgw_topology"] = NewRGWTopology
+}
+
+func NewRGWTopology() (Collector, error) {
+	return &RGWTopology{}, nil
+}
+
+func (c *RGWTopology) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	localZone, err := rgwGetLocalZone(ctx, client.RGWAdminAPI)
+	if err != nil {
+		return err
+	}
+
+	period, err := rgwGetPeriod(ctx, client.RGWAdminAPI)
+	if err != nil {
+		return err
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "realm_info"),
+		"RGW Realm info",
+		nil, map[string]string{
+			"realm":      client.Name,
+			"realm_id":   period.RealmID,
+			"realm_name": period.RealmName,
+			"period_id":  period.ID,
+		})
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	labels := map[string]string{
+		"realm":     client.Name,
+		"realm_id":  period.RealmID,
+		"period_id": period.ID,
+	}
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "period_epoch"),
+		"RGW Period epoch",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(period.Epoch))
+
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "period_realm_epoch"),
+		"RGW Period realm epoch",
+		nil, labels)
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, float64(period.RealmEpoch))
+
+	localZoneGroup := ""
+	for _, zoneGroup := range period.PeriodMap.Zonegroups {
+		masterZone := zoneGroup.MasterZone
+		for _, zone := range zoneGroup.Zones {
+			if zone.ID == zoneGroup.MasterZone {
+				masterZone = zone.Name
+			}
+			if zone.ID == localZone.ID {
+				localZoneGroup = zoneGroup.Name
+			}
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "zonegroup_info"),
+			"RGW Zonegroup info",
+			nil, map[string]string{
+				"realm":        client.Name,
+				"zonegroup":    zoneGroup.Name,
+				"zonegroup_id": zoneGroup.ID,
+				"api_name":     zoneGroup.APIName,
+				"is_master":    strconv.FormatBool(zoneGroup.ID == period.MasterZonegroup),
+				"master_zone":  masterZone,
+				"endpoints":    strings.Join(zoneGroup.Endpoints, ","),
+			})
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, 1)
+
+		for _, zone := range zoneGroup.Zones {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rgw", "zone_info"),
+				"RGW Zone info",
+				nil, map[string]string{
+					"realm":     client.Name,
+					"zonegroup": zoneGroup.Name,
+					"zone":      zone.Name,
+					"zone_id":   zone.ID,
+					"tier_type": zone.TierType,
+					"is_master": strconv.FormatBool(zone.ID == zoneGroup.MasterZone),
+					"endpoints": strings.Join(zone.Endpoints, ","),
+				})
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, 1)
+		}
+
+		for _, target := range zoneGroup.PlacementTargets {
+			// Placement targets always have the standard storage class
+			storageClasses := target.StorageClasses
+			if len(storageClasses) == 0 {
+				storageClasses = []string{"STANDARD"}
+			}
+
+			for _, storageClass := range storageClasses {
+				c.current = prometheus.NewDesc(
+					prometheus.BuildFQName(MetricsNamespace, "rgw", "zonegroup_placement_target_info"),
+					"RGW Zonegroup placement target and storage class info",
+					nil, map[string]string{
+						"realm":            client.Name,
+						"zonegroup":        zoneGroup.Name,
+						"placement_target": target.Name,
+						"storage_class":    storageClass,
+						"default":          strconv.FormatBool(target.Name == zoneGroup.DefaultPlacement),
+					})
+				ch <- prometheus.MustNewConstMetric(
+					c.current, prometheus.GaugeValue, 1)
+			}
+		}
+	}
+
+	// Which zone(group) the configured realm endpoint belongs to
+	c.current = prometheus.NewDesc(
+		prometheus.BuildFQName(MetricsNamespace, "rgw", "endpoint_zone_info"),
+		"RGW configured realm endpoint zone info",
+		nil, map[string]string{
+			"realm":     client.Name,
+			"zonegroup": localZoneGroup,
+			"zone":      localZone.Name,
+			"zone_id":   localZone.ID,
+		})
+	ch <- prometheus.MustNewConstMetric(
+		c.current, prometheus.GaugeValue, 1)
+
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// rgwUsageTimeLayout is the time format of the usage log start and end parameters
+	rgwUsageTimeLayout = "2006-01-02 15:04:05"
+	// rgwUsageSettleTime is the time after which an hour of the usage log isn't updated anymore
+	rgwUsageSettleTime = time.Hour
+)
+
+type rgwUsageKey struct {
+	user     string
+	bucket   string
+	category string
+}
+
+type rgwUsageCounters struct {
+	ops           uint64
+	successfulOps uint64
+	bytesSent     uint64
+	bytesReceived uint64
+}
+
+func (c *rgwUsageCounters) add(o rgwUsageCounters) {
+	c.ops += o.ops
+	c.successfulOps += o.successfulOps
+	c.bytesSent += o.bytesSent
+	c.bytesReceived += o.bytesReceived
+}
+
+// rgwUsageState is the summed up usage log of a realm. The usage log has one entry per bucket per hour,
+// the entries of settled hours are only added once, so each scrape only needs the entries of the recent hours.
+type rgwUsageState struct {
+	// Start is the time the usage log entries are requested from, nothing is added before it
+	Start time.Time
+	// Settled is the usage of the entries before the start time
+	Settled map[rgwUsageKey]*rgwUsageCounters
+}
+
+type RGWUsage struct {
+	current *prometheus.Desc
+
+	mutex  sync.Mutex
+	states map[string]*rgwUsageState
+}
+
+func init() {
+	Factories["rgw_usage"] = NewRGWUsage
+}
+
+func NewRGWUsage() (Collector, error) {
+	return &RGWUsage{
+		states: map[string]*rgwUsageState{},
+	}, nil
+}
+
+func (c *RGWUsage) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	// The usage state is shared by the realms
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	state, ok := c.states[client.Name]
+	if !ok {
+		state = &rgwUsageState{
+			Settled: map[rgwUsageKey]*rgwUsageCounters{},
+		}
+		c.states[client.Name] = state
+	}
+
+	showEntries := true
+	showSummary := false
+	req := admin.Usage{
+		ShowEntries: &showEntries,
+		ShowSummary: &showSummary,
+	}
+	// The whole usage log is only requested by the first scrape
+	if !state.Start.IsZero() {
+		req.Start = state.Start.UTC().Format(rgwUsageTimeLayout)
+	}
+
+	usage, err := client.RGWAdminAPI.GetUsage(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	totals := state.add(usage, time.Now().Truncate(time.Hour).Add(-rgwUsageSettleTime))
+
+	// When the usage log is disabled (`rgw_enable_usage_log`), RGW returns no
+	// entries, so there is nothing to export for this realm
+	for key, total := range totals {
+		labels := map[string]string{
+			"uid":      key.user,
+			"bucket":   key.bucket,
+			"category": key.category,
+			"realm":    client.Name,
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "usage_ops_total"),
+			"RGW Usage operations",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.CounterValue, float64(total.ops))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "usage_successful_ops_total"),
+			"RGW Usage successful operations",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.CounterValue, float64(total.successfulOps))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "usage_sent_bytes_total"),
+			"RGW Usage bytes sent",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.CounterValue, float64(total.bytesSent))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "usage_received_bytes_total"),
+			"RGW Usage bytes received",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.CounterValue, float64(total.bytesReceived))
+	}
+
+	return nil
+}
+
+// add sums up the usage log entries (requested from the start time) and returns the totals. The entries before
+// the settled time are added to the settled usage and the start time is moved to the settled time, so they
+// aren't requested again. The settled usage isn't lowered by a trim of the usage log, it is only reset when
+// the exporter is restarted (and then only contains the retained usage log).
+func (s *rgwUsageState) add(usage admin.Usage, settled time.Time) map[rgwUsageKey]*rgwUsageCounters {
+	totals := map[rgwUsageKey]*rgwUsageCounters{}
+	for key, settledTotal := range s.Settled {
+		total := *settledTotal
+		totals[key] = &total
+	}
+
+	for _, entry := range usage.Entries {
+		for _, bucket := range entry.Buckets {
+			for _, category := range bucket.Categories {
+				key := rgwUsageKey{
+					user:     entry.User,
+					bucket:   bucket.Bucket,
+					category: category.Category,
+				}
+				counters := rgwUsageCounters{
+					ops:           category.Ops,
+					successfulOps: category.SuccessfulOps,
+					bytesSent:     category.BytesSent,
+					bytesReceived: category.BytesReceived,
+				}
+
+				total, ok := totals[key]
+				if !ok {
+					total = &rgwUsageCounters{}
+					totals[key] = total
+				}
+				total.add(counters)
+
+				if !time.Unix(int64(bucket.Epoch), 0).Before(settled) {
+					continue
+				}
+
+				settledTotal, ok := s.Settled[key]
+				if !ok {
+					settledTotal = &rgwUsageCounters{}
+					s.Settled[key] = settledTotal
+				}
+				settledTotal.add(counters)
+			}
+		}
+	}
+
+	if settled.After(s.Start) {
+		s.Start = settled
+	}
+
+	return totals
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+)
+
+// testRGWUsage returns a usage log with one entry of the `test` bucket per hour and 10 ops per hour.
+func testRGWUsage(t *testing.T, hours ...time.Time) admin.Usage {
+	t.Helper()
+
+	buckets := []string{}
+	for _, hour := range hours {
+		buckets = append(buckets, fmt.Sprintf(`{"bucket": "test", "epoch": %d, "owner": "user", "categories": [{"category": "put_obj", "bytes_sent": 0, "bytes_received": 100, "ops": 10, "successful_ops": 9}]}`, hour.Unix()))
+	}
+
+	usage := admin.Usage{}
+	if err := json.Unmarshal([]byte(`{"entries": [{"user": "user", "buckets": [`+strings.Join(buckets, ",")+`]}]}`), &usage); err != nil {
+		t.Fatalf("failed to decode usage. %v", err)
+	}
+
+	return usage
+}
+
+func TestRGWUsageStateAdd(t *testing.T) {
+	base := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	hour := func(h int) time.Time {
+		return base.Add(time.Duration(h) * time.Hour)
+	}
+	key := rgwUsageKey{user: "user", bucket: "test", category: "put_obj"}
+
+	state := &rgwUsageState{
+		Settled: map[rgwUsageKey]*rgwUsageCounters{},
+	}
+
+	// The first scrape gets the whole usage log, hour 0 is settled
+	totals := state.add(testRGWUsage(t, hour(0), hour(1), hour(2)), hour(1))
+	if got := totals[key]; got == nil || got.ops != 30 || got.successfulOps != 27 || got.bytesReceived != 300 {
+		t.Errorf("first scrape totals = %+v, want 30 ops", got)
+	}
+	if !state.Start.Equal(hour(1)) || state.Settled[key].ops != 10 {
+		t.Errorf("expected usage to be settled up to hour 1, got start %s and %+v", state.Start, state.Settled[key])
+	}
+
+	// The usage log has been trimmed, the settled usage stays
+	totals = state.add(testRGWUsage(t, hour(1), hour(2), hour(3)), hour(2))
+	if got := totals[key]; got == nil || got.ops != 40 {
+		t.Errorf("second scrape totals = %+v, want 40 ops", got)
+	}
+	if !state.Start.Equal(hour(2)) || state.Settled[key].ops != 20 {
+		t.Errorf("expected usage to be settled up to hour 2, got start %s and %+v", state.Start, state.Settled[key])
+	}
+
+	// Nothing new, the settled time doesn't move back
+	totals = state.add(testRGWUsage(t), hour(1))
+	if got := totals[key]; got == nil || got.ops != 20 {
+		t.Errorf("third scrape totals = %+v, want 20 ops", got)
+	}
+	if !state.Start.Equal(hour(2)) {
+		t.Errorf("expected start to stay at hour 2, got %s", state.Start)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+type RGWUserInfo struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rgw_user_info"] = NewRGWUserInfo
+}
+
+func NewRGWUserInfo() (Collector, error) {
+	return &RGWUserInfo{}, nil
+}
+
+func (c *RGWUserInfo) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	users, err := client.RGWAdminAPI.GetUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, user := range *users {
+		userInfo, err := client.RGWAdminAPI.GetUser(ctx, admin.User{
+			ID: user,
+		})
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get user %q info. %w", user, err))
+			continue
+		}
+
+		buckets, err := client.RGWAdminAPI.ListUsersBuckets(ctx, user)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to list user %q buckets. %w", user, err))
+			continue
+		}
+
+		labels := map[string]string{
+			"uid":   user,
+			"realm": client.Name,
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "user_info"),
+			"RGW User info",
+			nil, map[string]string{
+				"uid":               user,
+				"realm":             client.Name,
+				"display_name":      userInfo.DisplayName,
+				"tenant":            userInfo.Tenant,
+				"default_placement": userInfo.DefaultPlacement,
+			})
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, 1)
+
+		suspended := 0.0
+		if userInfo.Suspended != nil && *userInfo.Suspended != 0 {
+			suspended = 1
+		}
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "user_suspended"),
+			"RGW User suspended",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, suspended)
+
+		if userInfo.MaxBuckets != nil {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rgw", "user_max_buckets"),
+				"RGW User max buckets (0 = unlimited, negative = bucket creation disabled)",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(*userInfo.MaxBuckets))
+		}
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "user_buckets"),
+			"RGW User number of buckets",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(len(buckets)))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "user_s3_keys"),
+			"RGW User number of S3 keys",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(len(userInfo.Keys)))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "user_swift_keys"),
+			"RGW User number of Swift keys",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(len(userInfo.SwiftKeys)))
+
+		c.current = prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, "rgw", "user_subusers"),
+			"RGW User number of subusers",
+			nil, labels)
+		ch <- prometheus.MustNewConstMetric(
+			c.current, prometheus.GaugeValue, float64(len(userInfo.Subusers)))
+
+		for _, userCap := range userInfo.Caps {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rgw", "user_cap"),
+				"RGW User admin capability",
+				nil, map[string]string{
+					"uid":   user,
+					"realm": client.Name,
+					"type":  userCap.Type,
+					"perm":  userCap.Perm,
+				})
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, 1)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+)
+
+// rgwUserWithStats is the user info with the storage stats returned by the RGW admin API, the go-ceph
+// `admin.UserStat` lacks the utilized size (and expects the actual size as `size_rounded`).
+type rgwUserWithStats struct {
+	admin.User
+	Stats struct {
+		Size         *uint64 `json:"size"`
+		SizeActual   *uint64 `json:"size_actual"`
+		SizeUtilized *uint64 `json:"size_utilized"`
+		NumObjects   *uint64 `json:"num_objects"`
+	} `json:"stats"`
+}
+
+type RGWUserStats struct {
+	current *prometheus.Desc
+}
+
+func init() {
+	Factories["rgw_user_stats"] = NewRGWUserStats
+}
+
+func NewRGWUserStats() (Collector, error) {
+	return &RGWUserStats{}, nil
+}
+
+func (c *RGWUserStats) Update(ctx context.Context, client *Client, ch chan<- prometheus.Metric) error {
+	users, err := client.RGWAdminAPI.GetUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, user := range *users {
+		userInfo := &rgwUserWithStats{}
+		if err := rgwAdminGet(ctx, client.RGWAdminAPI, "/user", url.Values{
+			"uid":   []string{user},
+			"stats": []string{"true"},
+		}, userInfo); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to get user %q stats. %w", user, err))
+			continue
+		}
+
+		labels := map[string]string{
+			"uid":   user,
+			"realm": client.Name,
+		}
+
+		for metric, value := range map[string]struct {
+			help  string
+			value *uint64
+		}{
+			"user_size":          {"RGW User Size", userInfo.Stats.Size},
+			"user_size_actual":   {"RGW User Size actual (rounded to the allocation unit)", userInfo.Stats.SizeActual},
+			"user_size_utilized": {"RGW User Size utilized (after compression)", userInfo.Stats.SizeUtilized},
+			"user_num_objects":   {"RGW User Num Objects", userInfo.Stats.NumObjects},
+		} {
+			// Not all stats are returned by all RGW versions
+			if value.value == nil {
+				continue
+			}
+
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rgw", metric),
+				value.help,
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(*value.value))
+		}
+
+		quota := userInfo.UserQuota
+		// If quota nil/disabled, skip the ratios
+		if quota.Enabled == nil || !*quota.Enabled {
+			continue
+		}
+
+		// RGW checks the size quota against the actual (rounded) size, unless `check_on_raw` is set
+		size := userInfo.Stats.SizeActual
+		if quota.CheckOnRaw || size == nil {
+			size = userInfo.Stats.Size
+		}
+
+		// Negative max values mean the quota is unlimited
+		if quota.MaxSize != nil && *quota.MaxSize > 0 && size != nil {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rgw", "user_quota_size_used_ratio"),
+				"RGW User Quota size used ratio",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(*size)/float64(*quota.MaxSize))
+		}
+
+		if quota.MaxObjects != nil && *quota.MaxObjects > 0 && userInfo.Stats.NumObjects != nil {
+			c.current = prometheus.NewDesc(
+				prometheus.BuildFQName(MetricsNamespace, "rgw", "user_quota_objects_used_ratio"),
+				"RGW User Quota objects used ratio",
+				nil, labels)
+			ch <- prometheus.MustNewConstMetric(
+				c.current, prometheus.GaugeValue, float64(*userInfo.Stats.NumObjects)/float64(*quota.MaxObjects))
+		}
+	}
+
+	return errs
+}
+
+func uint64PtrToFloat(v *uint64) float64 {
+	if v == nil {
+		return 0.0
+	}
+
+	return float64(*v)
+}
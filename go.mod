@@ -5,6 +5,8 @@ go 1.26.3
 toolchain go1.26.5
 
 require (
+	github.com/aws/aws-sdk-go-v2 v1.43.2
+	github.com/aws/aws-sdk-go-v2/credentials v1.19.32
 	github.com/ceph/go-ceph v0.41.0
 	github.com/creasty/defaults v1.8.0
 	github.com/mitchellh/mapstructure v1.5.0
@@ -17,8 +19,6 @@ require (
 )
 
 require (
-	github.com/aws/aws-sdk-go-v2 v1.43.2 // indirect
-	github.com/aws/aws-sdk-go-v2/credentials v1.19.32 // indirect
 	github.com/aws/smithy-go v1.27.5 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
@@ -109,9 +109,9 @@ func main() {
 
 	var radosConn *rados.Conn
 	if slices.ContainsFunc(opts.CollectorsEnabled, func(c string) bool {
-		return strings.HasPrefix(c, "rbd_")
+		return strings.HasPrefix(c, "rbd_") || strings.HasPrefix(c, "rados_") || strings.HasPrefix(c, "cephfs_") || c == "ceph_command"
 	}) {
-		radosConn, err := rados.NewConn()
+		radosConn, err = rados.NewConn()
 		if err != nil {
 			logger.Fatal("failed to create new rados connection", zap.Error(err))
 		}
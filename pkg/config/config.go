@@ -45,7 +45,15 @@ type Config struct {
 
 	Cache Cache `yaml:"cache"`
 
+	RGW RGWOptions `yaml:"rgw"`
+
 	RBD RBD `yaml:"rbd"`
+
+	RADOS RADOSOptions `yaml:"rados"`
+
+	CephFS CephFS `yaml:"cephfs"`
+
+	Commands []*CephCommand `yaml:"commands"`
 }
 
 type Timeouts struct {
@@ -58,12 +66,151 @@ type Cache struct {
 	Duration time.Duration `yaml:"duration" default:"20s"`
 }
 
+type RGWOptions struct {
+	Buckets     RGWBuckets     `yaml:"buckets"`
+	BucketIndex RGWBucketIndex `yaml:"bucketIndex"`
+	Sync        RGWSync        `yaml:"sync"`
+}
+
+type RGWBuckets struct {
+	// Collect the usage per category (e.g., `rgw.multimeta`), needs one additional request per bucket
+	Categories bool `yaml:"categories"`
+}
+
+type RGWBucketIndex struct {
+	// Should match the `rgw_max_objs_per_shard` option of the RGWs
+	MaxObjectsPerShard uint64 `yaml:"maxObjectsPerShard" default:"100000"`
+}
+
+type RGWSync struct {
+	// Override the zone endpoints from the period (e.g., when the RGWs are behind a load balancer)
+	ZoneEndpoints []*RGWZoneEndpoint `yaml:"zoneEndpoints"`
+	// Buckets to collect the per-bucket sync status for
+	Buckets []string `yaml:"buckets"`
+}
+
+type RGWZoneEndpoint struct {
+	Name     string `yaml:"name"`
+	Endpoint string `yaml:"endpoint"`
+}
+
 type RBD struct {
 	CephConfig string     `yaml:"cephConfig"`
 	Pools      []*RBDPool `yaml:"pools"`
+
+	DU       RBDDiskUsage     `yaml:"du"`
+	Metadata RBDImageMetadata `yaml:"metadata"`
+	Watchers RBDWatchers      `yaml:"watchers"`
+}
+
+const (
+	RBDDiskUsageModeSkip = "skip"
+	RBDDiskUsageModeSlow = "slow"
+)
+
+type RBDDiskUsage struct {
+	// How to handle images without the fast-diff feature (`skip` or `slow`)
+	NoFastDiffMode string `yaml:"noFastDiffMode" default:"skip"`
+	// Number of images per pool to calculate the disk usage for concurrently
+	Concurrency int `yaml:"concurrency" default:"4"`
+	// Timeout for calculating the disk usage of a single image (including its snapshots)
+	// The timeout is best-effort, it is checked between the snapshots and the changed extents of a diff
+	ImageTimeout time.Duration `yaml:"imageTimeout" default:"10s"`
+}
+
+const (
+	RBDImageMetadataPresetCephCSI = "ceph-csi"
+)
+
+type RBDImageMetadata struct {
+	// Presets of image metadata keys to expose as labels (`ceph-csi`), the `ceph-csi` preset is used if unset
+	// and an empty list disables the presets
+	Presets *[]string `yaml:"presets,omitempty"`
+	// Additional image metadata keys to expose as labels
+	Keys []*RBDImageMetadataKey `yaml:"keys"`
+}
+
+type RBDImageMetadataKey struct {
+	Key   string `yaml:"key"`
+	Label string `yaml:"label"`
+}
+
+type RBDWatchers struct {
+	// Emit a metric per watcher with the client address as label (high cardinality)
+	ClientAddresses bool `yaml:"clientAddresses"`
 }
 
 type RBDPool struct {
 	Name       string
 	Namespaces []string `yaml:"namespaces"`
 }
+
+type RADOSOptions struct {
+	Namespaces RADOSNamespaces `yaml:"namespaces"`
+}
+
+type RADOSNamespaces struct {
+	// Max number of objects to list per pool and scrape, the listing is continued on the next scrape (also within a
+	// placement group). Together with the objects per second this limits the listing time per pool and scrape.
+	MaxObjectsPerScrape int `yaml:"maxObjectsPerScrape" default:"10000"`
+	// Max number of objects to list per second (0 = unlimited)
+	ObjectsPerSecond int `yaml:"objectsPerSecond" default:"10000"`
+	// Stat every n-th object to estimate the bytes per namespace (0 = disabled)
+	StatSampleRate int `yaml:"statSampleRate" default:"10"`
+}
+
+type CephFS struct {
+	// Ceph Config file to read for the CephFS mounts (if left empty will read default Ceph config file)
+	CephConfig string `yaml:"cephConfig"`
+	// Filesystems (volumes) to collect metrics from (all filesystems if empty)
+	Filesystems []string `yaml:"filesystems"`
+	// Directories to collect the quota and recursive stats of
+	Directories []*CephFSDirectory `yaml:"directories"`
+	// Paths to get the snapshot schedule status of, the subvolume paths are always checked
+	SnapshotSchedulePaths []string      `yaml:"snapshotSchedulePaths" default:"[\"/\"]"`
+	Clients               CephFSClients `yaml:"clients"`
+}
+
+type CephFSClients struct {
+	// Emit a client info metric with the hostname, mount root and kernel/Ceph version as labels
+	Metadata bool `yaml:"metadata"`
+}
+
+type CephFSDirectory struct {
+	// Filesystem the directory is in (default filesystem if empty)
+	Filesystem string `yaml:"filesystem"`
+	Path       string `yaml:"path"`
+	// Depth of subdirectories to collect as well (0 = only the directory itself)
+	Depth int `yaml:"depth"`
+}
+
+const (
+	CephCommandTargetMon = "mon"
+	CephCommandTargetMgr = "mgr"
+)
+
+type CephCommand struct {
+	// Name of the command, must be unique
+	Name string `yaml:"name"`
+	// Target to run the command on, `mon` (default if empty) or `mgr`
+	Target string `yaml:"target"`
+	// Prefix of the command, e.g., `osd pool autoscale-status`
+	Prefix string `yaml:"prefix"`
+	// Args of the command, the JSON format is always requested
+	Args map[string]any `yaml:"args"`
+	// Interval to run the command at, the output is cached in between (0 = every scrape)
+	Interval time.Duration `yaml:"interval"`
+	// Metrics to create from the command output
+	Metrics []*CephCommandMetric `yaml:"metrics"`
+}
+
+type CephCommandMetric struct {
+	// Name of the metric (prefixed with `ceph_command_`), must be unique over all commands
+	Name string `yaml:"name"`
+	Help string `yaml:"help"`
+	// Selector of the values, e.g., `$.pools[*].stats.stored`
+	Selector string `yaml:"selector"`
+	// Labels and the selector of their value. Selectors are relative to the element matched by the last
+	// wildcard of the value selector, `$` prefixed selectors are absolute and `@key` is the key/index of the element
+	Labels map[string]string `yaml:"labels"`
+}
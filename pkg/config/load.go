@@ -84,9 +84,21 @@ func loadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := validateConfig(c); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return c, nil
 }
 
+func validateConfig(c *Config) error {
+	if mode := c.RBD.DU.NoFastDiffMode; mode != RBDDiskUsageModeSkip && mode != RBDDiskUsageModeSlow {
+		return fmt.Errorf("invalid rbd du no fast-diff mode %q (must be %q or %q)", mode, RBDDiskUsageModeSkip, RBDDiskUsageModeSlow)
+	}
+
+	return nil
+}
+
 func loadRealms(path string) (*RGW, error) {
 	v := viper.New()
 	// Viper reading setup
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 Alexander Trost All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadTestConfigRBDImageMetadataPresets(t *testing.T) {
+	c, _, err := LoadTestConfig()
+	if err != nil {
+		t.Fatalf("failed to load test config. %v", err)
+	}
+
+	// Unset presets use the default preset
+	if c.RBD.Metadata.Presets != nil {
+		t.Errorf("expected unset rbd image metadata presets, got %v", *c.RBD.Metadata.Presets)
+	}
+}
+
+func TestLoadConfigRBDImageMetadataPresets(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   *[]string
+	}{
+		{
+			name:   "unset",
+			config: "rbd:\n  metadata:\n    keys: []\n",
+			want:   nil,
+		},
+		{
+			name:   "empty list",
+			config: "rbd:\n  metadata:\n    presets: []\n",
+			want:   &[]string{},
+		},
+		{
+			name:   "preset",
+			config: "rbd:\n  metadata:\n    presets:\n      - ceph-csi\n",
+			want:   &[]string{RBDImageMetadataPresetCephCSI},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(test.config), 0o600); err != nil {
+				t.Fatalf("failed to write config. %v", err)
+			}
+
+			c, err := loadConfig(path)
+			if err != nil {
+				t.Fatalf("failed to load config. %v", err)
+			}
+
+			if !reflect.DeepEqual(c.RBD.Metadata.Presets, test.want) {
+				t.Errorf("rbd image metadata presets = %v, want %v", c.RBD.Metadata.Presets, test.want)
+			}
+		})
+	}
+}